@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Role identifies who is allowed to hold a token.
+type Role string
+
+const (
+	RoleMaster Role = "master"
+	RoleMapper Role = "mapper"
+)
+
+// SecretEnv is read when no secret file is given.
+const SecretEnv = "MAPREDUCE_AUTH_SECRET"
+
+// metadataKey is the gRPC metadata key carrying the token.
+const metadataKey = "authorization"
+
+var (
+	ErrMalformedToken = errors.New("malformed token")
+	ErrBadSignature   = errors.New("invalid token signature")
+	ErrExpiredToken   = errors.New("token expired")
+	ErrOtherJob       = errors.New("token issued for another job")
+)
+
+// Claims is the signed content of a token.
+type Claims struct {
+	Role    Role   `json:"role"`
+	Subject string `json:"sub"`
+	// Job binds the token to a job, empty for tokens valid in any job
+	Job     string `json:"job,omitempty"`
+	Expires int64  `json:"exp"`
+}
+
+// Authority issues and verifies tokens signed with a shared secret.
+type Authority struct {
+	secret []byte
+}
+
+func NewAuthority(secret []byte) *Authority {
+	return &Authority{secret: secret}
+}
+
+// LoadSecret reads the shared secret from path, or from SecretEnv if path is empty.
+// A nil secret means authentication is disabled.
+func LoadSecret(path string) ([]byte, error) {
+	if path == "" {
+		if s := os.Getenv(SecretEnv); s != "" {
+			return []byte(s), nil
+		}
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	secret := []byte(strings.TrimSpace(string(data)))
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("secret file %s is empty", path)
+	}
+	return secret, nil
+}
+
+// Issue returns a token granting role to subject for the given duration, only during job unless it is empty.
+func (a *Authority) Issue(role Role, subject, job string, ttl time.Duration) (string, error) {
+	payload, err := json.Marshal(Claims{
+		Role:    role,
+		Subject: subject,
+		Job:     job,
+		Expires: time.Now().Add(ttl).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(a.sign(encoded)), nil
+}
+
+// Verify checks the signature and expiry of token and returns its claims.
+func (a *Authority) Verify(token string) (*Claims, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrMalformedToken
+	}
+	rawSig, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	if !hmac.Equal(rawSig, a.sign(encoded)) {
+		return nil, ErrBadSignature
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrMalformedToken
+	}
+	if time.Now().Unix() > claims.Expires {
+		return nil, ErrExpiredToken
+	}
+	return &claims, nil
+}
+
+func (a *Authority) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+// UnaryServerInterceptor rejects calls without a valid token, and calls whose
+// token role is not listed for the method in rules. Methods missing from rules are denied.
+// A token bound to a job is only accepted while job returns the same job.
+func (a *Authority) UnaryServerInterceptor(rules map[string][]Role, job func() string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.authorize(ctx, info.FullMethod, rules, job); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor applies the same rules as UnaryServerInterceptor to streaming RPCs.
+func (a *Authority) StreamServerInterceptor(rules map[string][]Role, job func() string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authorize(ss.Context(), info.FullMethod, rules, job); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func (a *Authority) authorize(ctx context.Context, method string, rules map[string][]Role, job func() string) error {
+	values := metadata.ValueFromIncomingContext(ctx, metadataKey)
+	if len(values) == 0 {
+		return status.Error(codes.Unauthenticated, "missing token")
+	}
+	claims, err := a.Verify(strings.TrimPrefix(values[0], "Bearer "))
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	if claims.Job != "" && claims.Job != job() {
+		return status.Error(codes.PermissionDenied, ErrOtherJob.Error())
+	}
+	for _, role := range rules[method] {
+		if claims.Role == role {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "role %q may not call %s", claims.Role, method)
+}
+
+// UnaryClientInterceptor attaches the token returned by token to every call.
+// No metadata is added while token returns an empty string.
+func UnaryClientInterceptor(token func() string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withToken(ctx, token()), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor attaches the token returned by token to every stream.
+func StreamClientInterceptor(token func() string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withToken(ctx, token()), desc, cc, method, opts...)
+	}
+}
+
+func withToken(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, metadataKey, "Bearer "+token)
+}
@@ -0,0 +1,115 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"mapreduce/auth"
+	pb "mapreduce/proto"
+	"mapreduce/worker"
+)
+
+func issue(t *testing.T, a *auth.Authority, role auth.Role, job string, ttl time.Duration) string {
+	t.Helper()
+	token, err := a.Issue(role, "subject", job, ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestVerify(t *testing.T) {
+	a := auth.NewAuthority([]byte("secret"))
+	valid := issue(t, a, auth.RoleMapper, "job", time.Minute)
+	payload, sig, _ := strings.Cut(valid, ".")
+	master := issue(t, a, auth.RoleMaster, "", time.Minute)
+	masterPayload, _, _ := strings.Cut(master, ".")
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"signed by another secret", issue(t, auth.NewAuthority([]byte("other")), auth.RoleMapper, "job", time.Minute), auth.ErrBadSignature},
+		{"payload of another token", masterPayload + "." + sig, auth.ErrBadSignature},
+		{"no dot", payload + sig, auth.ErrMalformedToken},
+		{"bad base64 signature", payload + ".!!", auth.ErrMalformedToken},
+		{"bad base64 payload", "!!." + sig, auth.ErrBadSignature},
+		{"expired", issue(t, a, auth.RoleMapper, "job", -time.Minute), auth.ErrExpiredToken},
+	}
+	for _, tt := range tests {
+		if _, err := a.Verify(tt.token); !errors.Is(err, tt.err) {
+			t.Errorf("%s: Verify = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+
+	claims, err := a.Verify(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Role != auth.RoleMapper || claims.Subject != "subject" || claims.Job != "job" {
+		t.Errorf("Verify = %+v", claims)
+	}
+}
+
+// TestVerifyBadJSON signs payloads that are not valid claims, so only their decoding can fail
+func TestVerifyBadJSON(t *testing.T) {
+	a := auth.NewAuthority([]byte("secret"))
+	for _, payload := range []string{"not json", `{"role":1}`} {
+		encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(encoded))
+		token := encoded + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+		if _, err := a.Verify(token); !errors.Is(err, auth.ErrMalformedToken) {
+			t.Errorf("Verify(%q) = %v, want %v", payload, err, auth.ErrMalformedToken)
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	a := auth.NewAuthority([]byte("secret"))
+	interceptor := a.UnaryServerInterceptor(worker.MethodRoles, func() string { return "current" })
+	master := issue(t, a, auth.RoleMaster, "", time.Minute)
+	mapper := issue(t, a, auth.RoleMapper, "current", time.Minute)
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		code   codes.Code
+	}{
+		{"missing token", "", pb.WorkerService_AssignRole_FullMethodName, codes.Unauthenticated},
+		{"invalid token", "x.y", pb.WorkerService_AssignRole_FullMethodName, codes.Unauthenticated},
+		{"master token with empty job", master, pb.WorkerService_AssignRole_FullMethodName, codes.OK},
+		{"master calling a mapper method", master, pb.WorkerService_SendMappedData_FullMethodName, codes.PermissionDenied},
+		{"mapper calling a master method", mapper, pb.WorkerService_AssignRole_FullMethodName, codes.PermissionDenied},
+		{"mapper token of the current job", mapper, pb.WorkerService_SendMappedData_FullMethodName, codes.OK},
+		{"mapper token of another job", issue(t, a, auth.RoleMapper, "previous", time.Minute), pb.WorkerService_SendMappedData_FullMethodName, codes.PermissionDenied},
+		{"method missing from rules", master, "/mapreduce.WorkerService/Unknown", codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.token != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+tt.token))
+		}
+		called := false
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			called = true
+			return nil, nil
+		})
+		if code := status.Code(err); code != tt.code {
+			t.Errorf("%s: code %v, want %v (%v)", tt.name, code, tt.code, err)
+		}
+		if called != (tt.code == codes.OK) {
+			t.Errorf("%s: handler called = %v", tt.name, called)
+		}
+	}
+}
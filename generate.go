@@ -0,0 +1,31 @@
+package main
+
+import (
+	"math/rand"
+	"os"
+
+	"mapreduce/ioformat"
+)
+
+// generateInput writes count random values of type t to path, with uniformly random bits: integers are
+// uniform over their full range, floats span every exponent and include infinities and NaNs.
+func generateInput(path string, count int, format ioformat.Format, t ioformat.Type) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w, err := ioformat.NewWriter(f, format, t)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < count; i++ {
+		if err := w.Write(t.Key(rand.Uint64())); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Close()
+}
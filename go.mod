@@ -1,6 +1,6 @@
 module mapreduce
 
-go 1.20
+go 1.21
 
 require (
 	google.golang.org/grpc v1.64.0
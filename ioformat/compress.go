@@ -0,0 +1,73 @@
+package ioformat
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GzipSuffix is the file name extension of gzip-compressed files
+const GzipSuffix = ".gz"
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Decompress returns the content of r according to compression, which is "gzip", "none"
+// or "auto". Auto decompresses when name ends with GzipSuffix or r starts with the gzip magic number.
+// The returned closer releases the decompressor, it does not close r.
+func Decompress(r io.Reader, name, compression string) (io.Reader, io.Closer, error) {
+	switch compression {
+	case "none":
+		return r, nopCloser{}, nil
+	case "gzip":
+	case "", "auto":
+		br := bufio.NewReaderSize(r, bufferSize)
+		head, err := br.Peek(len(gzipMagic))
+		if err != nil && err != io.EOF {
+			return nil, nil, err
+		}
+		r = br
+		if !IsGzip(name, head) {
+			return r, nopCloser{}, nil
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown compression %q, expected auto, gzip or none", compression)
+	}
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return zr, zr, nil
+}
+
+// IsGzip reports whether the file name or the head of its content indicate gzip compression.
+func IsGzip(name string, head []byte) bool {
+	return strings.HasSuffix(name, GzipSuffix) || bytes.HasPrefix(head, gzipMagic)
+}
+
+// NewCompressedWriter returns a Writer encoding values into w, gzip-compressed when compress is set.
+// Call Close after Flush to complete the compressed stream, it does not close w.
+func NewCompressedWriter(w io.Writer, f Format, t Type, compress bool) (Writer, io.Closer, error) {
+	w, closer := Compress(w, compress)
+	enc, err := NewWriter(w, f, t)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, closer, nil
+}
+
+// Compress returns a writer gzip-compressing into w when compress is set, w itself otherwise.
+// Close completes the compressed stream, it does not close w.
+func Compress(w io.Writer, compress bool) (io.Writer, io.Closer) {
+	if !compress {
+		return w, nopCloser{}
+	}
+	zw := gzip.NewWriter(w)
+	return zw, zw
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,217 @@
+package ioformat
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Format is the encoding of a stream of values, see Type for their type.
+// The values match pb.DataFormat so that they can be converted directly.
+type Format int32
+
+const (
+	// Text is one decimal value per line
+	Text Format = iota
+	// BinaryLE is raw little-endian 8-byte values: two's complement, unsigned or IEEE 754 floats
+	BinaryLE
+	// BinaryBE is raw big-endian 8-byte values
+	BinaryBE
+	// Auto detects Text or BinaryLE from the beginning of the input, see Detect
+	Auto Format = -1
+)
+
+const bufferSize = 1 << 20
+
+// Parse reads a format name as given on the command line.
+func Parse(name string) (Format, error) {
+	switch name {
+	case "text":
+		return Text, nil
+	case "binary-le", "binary":
+		return BinaryLE, nil
+	case "binary-be":
+		return BinaryBE, nil
+	case "auto":
+		return Auto, nil
+	}
+	return 0, fmt.Errorf("unknown format %q, expected text, binary-le, binary-be or auto", name)
+}
+
+func (f Format) String() string {
+	switch f {
+	case Text:
+		return "text"
+	case BinaryLE:
+		return "binary-le"
+	case BinaryBE:
+		return "binary-be"
+	case Auto:
+		return "auto"
+	}
+	return fmt.Sprintf("Format(%d)", int32(f))
+}
+
+// Detect returns Text if head only contains characters of lines of values of type t,
+// BinaryLE otherwise. Endianness cannot be detected, use BinaryBE explicitly.
+func Detect(head []byte, t Type) Format {
+	for _, c := range head {
+		if !t.textByte(c) {
+			return BinaryLE
+		}
+	}
+	return Text
+}
+
+// Reader decodes values one at a time into their int64 keys. Read returns io.EOF after the last value.
+type Reader interface {
+	Read() (int64, error)
+}
+
+// NewReader returns a buffered Reader decoding values of type t from r. Auto is resolved by peeking at r.
+func NewReader(r io.Reader, f Format, t Type) (Reader, error) {
+	br := bufio.NewReaderSize(r, bufferSize)
+	if f == Auto {
+		head, err := br.Peek(4096)
+		if err != nil && err != io.EOF && !errors.Is(err, bufio.ErrBufferFull) {
+			return nil, err
+		}
+		f = Detect(head, t)
+	}
+	switch f {
+	case Text:
+		return &textReader{r: br, t: t}, nil
+	case BinaryLE:
+		return &binaryReader{r: br, t: t, order: binary.LittleEndian}, nil
+	case BinaryBE:
+		return &binaryReader{r: br, t: t, order: binary.BigEndian}, nil
+	}
+	return nil, fmt.Errorf("unsupported input format %v", f)
+}
+
+// ReadAll decodes every value of type t of r into its key.
+func ReadAll(r io.Reader, f Format, t Type) ([]int64, error) {
+	dec, err := NewReader(r, f, t)
+	if err != nil {
+		return nil, err
+	}
+	var values []int64
+	for {
+		v, err := dec.Read()
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return values, err
+		}
+		values = append(values, v)
+	}
+}
+
+type textReader struct {
+	r    *bufio.Reader
+	t    Type
+	line int
+}
+
+func (t *textReader) Read() (int64, error) {
+	for {
+		raw, err := t.r.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			return 0, fmt.Errorf("line %d: too long", t.line+1)
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		if len(raw) == 0 && err == io.EOF {
+			return 0, io.EOF
+		}
+		t.line++
+		field := bytes.TrimSpace(raw)
+		if len(field) == 0 {
+			if err == io.EOF {
+				return 0, io.EOF
+			}
+			continue
+		}
+		v, perr := t.t.ParseKey(string(field))
+		if perr != nil {
+			return 0, fmt.Errorf("line %d: %w", t.line, perr)
+		}
+		return v, nil
+	}
+}
+
+type binaryReader struct {
+	r     *bufio.Reader
+	t     Type
+	order binary.ByteOrder
+	buf   [8]byte
+}
+
+func (b *binaryReader) Read() (int64, error) {
+	n, err := io.ReadFull(b.r, b.buf[:])
+	if err == io.EOF {
+		return 0, io.EOF
+	}
+	if err == io.ErrUnexpectedEOF {
+		return 0, fmt.Errorf("truncated value: %d trailing bytes", n)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return b.t.Key(b.order.Uint64(b.buf[:])), nil
+}
+
+// Writer encodes values given by their int64 keys. Flush must be called after the last value.
+type Writer interface {
+	Write(v int64) error
+	Flush() error
+}
+
+// NewWriter returns a buffered Writer encoding values of type t to w.
+func NewWriter(w io.Writer, f Format, t Type) (Writer, error) {
+	bw := bufio.NewWriterSize(w, bufferSize)
+	switch f {
+	case Text:
+		return &textWriter{w: bw, t: t}, nil
+	case BinaryLE:
+		return &binaryWriter{w: bw, t: t, order: binary.LittleEndian}, nil
+	case BinaryBE:
+		return &binaryWriter{w: bw, t: t, order: binary.BigEndian}, nil
+	}
+	return nil, fmt.Errorf("unsupported output format %v", f)
+}
+
+type textWriter struct {
+	w   *bufio.Writer
+	t   Type
+	buf []byte
+}
+
+func (t *textWriter) Write(v int64) error {
+	t.buf = t.t.AppendKey(t.buf[:0], v)
+	t.buf = append(t.buf, '\n')
+	_, err := t.w.Write(t.buf)
+	return err
+}
+
+func (t *textWriter) Flush() error { return t.w.Flush() }
+
+type binaryWriter struct {
+	w     *bufio.Writer
+	t     Type
+	order binary.ByteOrder
+	buf   [8]byte
+}
+
+func (b *binaryWriter) Write(v int64) error {
+	b.order.PutUint64(b.buf[:], b.t.Bits(v))
+	_, err := b.w.Write(b.buf[:])
+	return err
+}
+
+func (b *binaryWriter) Flush() error { return b.w.Flush() }
@@ -0,0 +1,100 @@
+package ioformat
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// KeyField extracts the sort key of a text record from one of its fields.
+type KeyField struct {
+	// Field is the 1-based index of the key field, 0 for the whole line
+	Field int
+	// Delimiter separates fields, "," when empty
+	Delimiter string
+}
+
+// Bytes returns the key field of line, unchanged.
+func (k KeyField) Bytes(line []byte) ([]byte, error) {
+	if k.Field < 0 {
+		return nil, errors.New("key field must not be negative")
+	}
+	if k.Field == 0 {
+		return line, nil
+	}
+	delim := []byte(k.Delimiter)
+	if len(delim) == 0 {
+		delim = []byte(",")
+	}
+	rest := line
+	for i := 1; i < k.Field; i++ {
+		j := bytes.Index(rest, delim)
+		if j < 0 {
+			return nil, fmt.Errorf("no field %d", k.Field)
+		}
+		rest = rest[j+len(delim):]
+	}
+	if j := bytes.Index(rest, delim); j >= 0 {
+		rest = rest[:j]
+	}
+	return rest, nil
+}
+
+// LineReader reads the non-blank lines of text records.
+type LineReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+func NewLineReader(r io.Reader) *LineReader {
+	return &LineReader{r: bufio.NewReaderSize(r, bufferSize)}
+}
+
+// Read returns a copy of the next non-blank line without its line ending.
+// It returns io.EOF after the last line.
+func (lr *LineReader) Read() ([]byte, error) {
+	for {
+		raw, err := lr.r.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			return nil, fmt.Errorf("line %d: too long", lr.line+1)
+		}
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if len(raw) == 0 && err == io.EOF {
+			return nil, io.EOF
+		}
+		lr.line++
+		line := bytes.TrimRight(raw, "\r\n")
+		if len(bytes.TrimSpace(line)) == 0 {
+			if err == io.EOF {
+				return nil, io.EOF
+			}
+			continue
+		}
+		return bytes.Clone(line), nil
+	}
+}
+
+// Line is the number of the line last read, starting at 1.
+func (lr *LineReader) Line() int { return lr.line }
+
+// RecordWriter writes records back as lines, in the order they are given.
+type RecordWriter struct {
+	w *bufio.Writer
+}
+
+func NewRecordWriter(w io.Writer) *RecordWriter {
+	return &RecordWriter{w: bufio.NewWriterSize(w, bufferSize)}
+}
+
+func (rw *RecordWriter) Write(line []byte) error {
+	if _, err := rw.w.Write(line); err != nil {
+		return err
+	}
+	return rw.w.WriteByte('\n')
+}
+
+func (rw *RecordWriter) Flush() error { return rw.w.Flush() }
@@ -0,0 +1,128 @@
+package ioformat
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Type is the numeric type of values. Values of every type are carried as int64 sort keys, whose
+// order is the order of the values, and converted back when written.
+type Type int32
+
+const (
+	// Int64 values are their own keys
+	Int64 Type = iota
+	// Uint64 keys are the values with their top bit flipped
+	Uint64
+	// Float64 keys follow the IEEE 754 total order: -NaN, -Inf, negative numbers, -0, +0,
+	// positive numbers, +Inf, +NaN
+	Float64
+)
+
+const signBit = 1 << 63
+
+func (t Type) String() string {
+	switch t {
+	case Int64:
+		return "int64"
+	case Uint64:
+		return "uint64"
+	case Float64:
+		return "float64"
+	}
+	return fmt.Sprintf("Type(%d)", int32(t))
+}
+
+// Key returns the sort key of the value whose binary encoding is bits.
+func (t Type) Key(bits uint64) int64 {
+	switch t {
+	case Uint64:
+		return int64(bits ^ signBit)
+	case Float64:
+		// negative floats order backwards, flipping all their bits reverses them
+		if bits&signBit != 0 {
+			return int64(^bits ^ signBit)
+		}
+		return int64(bits)
+	}
+	return int64(bits)
+}
+
+// Bits returns the binary encoding of the value of key, the inverse of Key.
+func (t Type) Bits(key int64) uint64 {
+	switch t {
+	case Uint64:
+		return uint64(key) ^ signBit
+	case Float64:
+		if key < 0 {
+			return ^(uint64(key) ^ signBit)
+		}
+		return uint64(key)
+	}
+	return uint64(key)
+}
+
+// ParseKey parses a value written in decimal, or for floats any form accepted by
+// strconv.ParseFloat, NaN and Inf included, as well as -NaN for a NaN with the sign bit set,
+// and returns its key.
+func (t Type) ParseKey(s string) (int64, error) {
+	switch t {
+	case Uint64:
+		u, err := strconv.ParseUint(s, 10, 64)
+		return t.Key(u), err
+	case Float64:
+		if strings.EqualFold(s, "-nan") {
+			return t.Key(math.Float64bits(math.NaN()) | signBit), nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		return t.Key(math.Float64bits(f)), err
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// AppendKey appends the value of key in text to dst. Floats use the shortest form that parses back
+// to the same value, and NaNs keep their sign, which orders them, but not their payload.
+func (t Type) AppendKey(dst []byte, key int64) []byte {
+	switch t {
+	case Uint64:
+		return strconv.AppendUint(dst, t.Bits(key), 10)
+	case Float64:
+		bits := t.Bits(key)
+		f := math.Float64frombits(bits)
+		if math.IsNaN(f) && bits&signBit != 0 {
+			return append(dst, "-NaN"...)
+		}
+		return strconv.AppendFloat(dst, f, 'g', -1, 64)
+	}
+	return strconv.AppendInt(dst, key, 10)
+}
+
+// AppendCount appends a line of a histogram to dst, the value of key and its count separated by a comma,
+// without line end.
+func (t Type) AppendCount(dst []byte, key, count int64) []byte {
+	dst = t.AppendKey(dst, key)
+	dst = append(dst, ',')
+	return strconv.AppendInt(dst, count, 10)
+}
+
+// FormatKey returns the value of key in text.
+func (t Type) FormatKey(key int64) string {
+	return string(t.AppendKey(nil, key))
+}
+
+// textByte reports whether c may appear in a text value of type t
+func (t Type) textByte(c byte) bool {
+	switch {
+	case c >= '0' && c <= '9', c == '-', c == '+', c == '\n', c == '\r', c == ' ', c == '\t':
+		return true
+	case t == Float64:
+		// decimal point, exponents, hexadecimal floats, NaN and Inf or Infinity in any case
+		switch c | 0x20 {
+		case '.', 'e', 'p', 'x', 'a', 'b', 'c', 'd', 'f', 'i', 'n', 't', 'y':
+			return true
+		}
+	}
+	return false
+}
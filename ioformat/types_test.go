@@ -0,0 +1,84 @@
+package ioformat
+
+import (
+	"math"
+	"testing"
+)
+
+var (
+	negNaN  = math.Float64frombits(0xfff8000000000001)
+	posNaN  = math.Float64frombits(0x7ff8000000000001)
+	negZero = math.Copysign(0, -1)
+)
+
+func TestFloat64KeyOrder(t *testing.T) {
+	// the IEEE 754 total order
+	ordered := []float64{negNaN, math.Inf(-1), -math.MaxFloat64, -1, -math.SmallestNonzeroFloat64, negZero,
+		0, math.SmallestNonzeroFloat64, 1, math.MaxFloat64, math.Inf(1), posNaN}
+	for i := 1; i < len(ordered); i++ {
+		a, b := Float64.Key(math.Float64bits(ordered[i-1])), Float64.Key(math.Float64bits(ordered[i]))
+		if a >= b {
+			t.Errorf("key of %v (%d) is not below key of %v (%d)", ordered[i-1], a, ordered[i], b)
+		}
+	}
+}
+
+func TestUint64KeyOrder(t *testing.T) {
+	ordered := []uint64{0, 1, math.MaxInt64, math.MaxInt64 + 1, math.MaxUint64 - 1, math.MaxUint64}
+	for i := 1; i < len(ordered); i++ {
+		if a, b := Uint64.Key(ordered[i-1]), Uint64.Key(ordered[i]); a >= b {
+			t.Errorf("key of %d (%d) is not below key of %d (%d)", ordered[i-1], a, ordered[i], b)
+		}
+	}
+}
+
+func TestNegativeNaNText(t *testing.T) {
+	// a negative NaN sorts first, and must still do after a text round trip
+	text := Float64.FormatKey(Float64.Key(math.Float64bits(negNaN)))
+	if text != "-NaN" {
+		t.Fatalf("negative NaN formatted as %q, want -NaN", text)
+	}
+	key, err := Float64.ParseKey(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inf := Float64.Key(math.Float64bits(math.Inf(-1))); key >= inf {
+		t.Errorf("key of %s (%d) is not below key of -Inf (%d)", text, key, inf)
+	}
+}
+
+func TestKeyBitsRoundTrip(t *testing.T) {
+	bits := []uint64{0, 1, math.MaxInt64, math.MaxInt64 + 1, math.MaxUint64,
+		math.Float64bits(negZero), math.Float64bits(math.Inf(1)), math.Float64bits(math.Inf(-1)),
+		math.Float64bits(negNaN), math.Float64bits(posNaN), math.Float64bits(-1.5)}
+	for _, typ := range []Type{Int64, Uint64, Float64} {
+		for _, b := range bits {
+			if got := typ.Bits(typ.Key(b)); got != b {
+				t.Errorf("%s: Bits(Key(%#x)) = %#x", typ, b, got)
+			}
+		}
+	}
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	tests := []struct {
+		typ    Type
+		values []string
+	}{
+		{Int64, []string{"0", "-1", "9223372036854775807", "-9223372036854775808"}},
+		{Uint64, []string{"0", "1", "9223372036854775808", "18446744073709551615"}},
+		{Float64, []string{"0", "-0", "1.5", "-1e-300", "5e-324", "1.7976931348623157e+308", "+Inf", "-Inf", "NaN", "-NaN", "-nan"}},
+	}
+	for _, tt := range tests {
+		for _, s := range tt.values {
+			key, err := tt.typ.ParseKey(s)
+			if err != nil {
+				t.Fatalf("%s: ParseKey(%q): %v", tt.typ, s, err)
+			}
+			again, err := tt.typ.ParseKey(tt.typ.FormatKey(key))
+			if err != nil || again != key {
+				t.Errorf("%s: %q formatted as %q parses to key %d, want %d", tt.typ, s, tt.typ.FormatKey(key), again, key)
+			}
+		}
+	}
+}
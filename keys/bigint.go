@@ -0,0 +1,84 @@
+package keys
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Big integers are encoded as a sign byte, 1 for negative numbers and 0 otherwise, followed by the
+// big-endian magnitude without leading zeros. Zero is a single 0 byte, so every number has exactly
+// one encoding.
+const negative = 1
+
+// EncodeBigInt returns the canonical encoding of x.
+func EncodeBigInt(x *big.Int) []byte {
+	sign := byte(0)
+	if x.Sign() < 0 {
+		sign = negative
+	}
+	mag := x.Bytes()
+	enc := make([]byte, 1+len(mag))
+	enc[0] = sign
+	copy(enc[1:], mag)
+	return enc
+}
+
+// DecodeBigInt returns the number encoded by EncodeBigInt.
+func DecodeBigInt(enc []byte) (*big.Int, error) {
+	if len(enc) == 0 {
+		return nil, errors.New("empty big integer encoding")
+	}
+	if enc[0] > negative || len(enc) > 1 && enc[1] == 0 || enc[0] == negative && len(enc) == 1 {
+		return nil, fmt.Errorf("non-canonical big integer encoding %x", enc)
+	}
+	x := new(big.Int).SetBytes(enc[1:])
+	if enc[0] == negative {
+		x.Neg(x)
+	}
+	return x, nil
+}
+
+// ParseBigInt parses a decimal integer of any size, with an optional sign, and returns its encoding.
+func ParseBigInt(s string) ([]byte, error) {
+	x, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %q", s)
+	}
+	return EncodeBigInt(x), nil
+}
+
+// compareBigInt orders encoded big integers by value: by sign, then by magnitude, which is larger
+// when it has more bytes as there are no leading zeros.
+func compareBigInt(a, b []byte) int {
+	if len(a) == 0 || len(b) == 0 {
+		return bytes.Compare(a, b)
+	}
+	if a[0] != b[0] {
+		// the negative one is smaller
+		return int(b[0]) - int(a[0])
+	}
+	c := len(a) - len(b)
+	if c == 0 {
+		c = bytes.Compare(a[1:], b[1:])
+	}
+	if c < 0 {
+		c = -1
+	} else if c > 0 {
+		c = 1
+	}
+	if a[0] == negative {
+		return -c
+	}
+	return c
+}
+
+// formatBigInt returns an encoded big integer in decimal
+func formatBigInt(enc []byte) string {
+	x, err := DecodeBigInt(enc)
+	if err != nil {
+		return fmt.Sprintf("invalid(%x)", enc)
+	}
+	return x.String()
+}
@@ -0,0 +1,77 @@
+package keys
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+var bigInts = []string{
+	"-340282366920938463463374607431768211456", "-18446744073709551616", "-65536", "-65535", "-256", "-255",
+	"-1", "0", "1", "255", "256", "65535", "65536", "18446744073709551616", "340282366920938463463374607431768211456",
+}
+
+func TestBigIntRoundTrip(t *testing.T) {
+	for _, s := range bigInts {
+		enc, err := ParseBigInt(s)
+		if err != nil {
+			t.Fatalf("ParseBigInt(%s): %v", s, err)
+		}
+		x, err := DecodeBigInt(enc)
+		if err != nil {
+			t.Fatalf("DecodeBigInt(%x): %v", enc, err)
+		}
+		if x.String() != s {
+			t.Errorf("round trip of %s = %s", s, x)
+		}
+		if got := formatBigInt(enc); got != s {
+			t.Errorf("formatBigInt(%x) = %s, want %s", enc, got, s)
+		}
+	}
+}
+
+func TestParseBigIntCanonical(t *testing.T) {
+	for s, want := range map[string][]byte{
+		"0":    {0},
+		"-0":   {0},
+		"+0":   {0},
+		"007":  {0, 7},
+		"+5":   {0, 5},
+		"-256": {negative, 1, 0},
+	} {
+		enc, err := ParseBigInt(s)
+		if err != nil {
+			t.Fatalf("ParseBigInt(%q): %v", s, err)
+		}
+		if !bytes.Equal(enc, want) {
+			t.Errorf("ParseBigInt(%q) = %x, want %x", s, enc, want)
+		}
+	}
+	for _, s := range []string{"", "-", "1.5", "0x10", "1e3", " 1"} {
+		if enc, err := ParseBigInt(s); err == nil {
+			t.Errorf("ParseBigInt(%q) = %x, want an error", s, enc)
+		}
+	}
+}
+
+func TestDecodeBigIntNonCanonical(t *testing.T) {
+	// empty, unknown sign, leading zero, negative zero
+	for _, enc := range [][]byte{{}, {2, 1}, {0, 0, 1}, {negative, 0, 1}, {negative}} {
+		if x, err := DecodeBigInt(enc); err == nil {
+			t.Errorf("DecodeBigInt(%x) = %s, want an error", enc, x)
+		}
+	}
+}
+
+func TestCompareBigInt(t *testing.T) {
+	for _, a := range bigInts {
+		for _, b := range bigInts {
+			x, _ := new(big.Int).SetString(a, 10)
+			y, _ := new(big.Int).SetString(b, 10)
+			ea, eb := EncodeBigInt(x), EncodeBigInt(y)
+			if got, want := compareBigInt(ea, eb), x.Cmp(y); got != want {
+				t.Errorf("compareBigInt(%s, %s) = %d, want %d", a, b, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package keys
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"mapreduce/ioformat"
+	pb "mapreduce/proto"
+)
+
+// Composite keys are the concatenation of their columns, each encoded so that the byte order of the
+// encodings is the order of the values, then inverted for descending columns. Every encoding ends
+// where the value does, so the remaining columns only break ties. Each column starts with a tag
+// byte holding its type and direction, the same in every record, which makes keys self-describing.
+//
+//	int64, uint64, float64: the int64 key with its top bit flipped, 8 bytes big-endian
+//	string: the bytes with 0x00 escaped as 0x00 0xff, followed by 0x00 0x01
+//	bigint: 0 for negative numbers and 1 otherwise, the length of the magnitude in 4 bytes
+//	big-endian and the magnitude, both inverted for negative numbers
+const descending = 1
+
+// columnTypes are the short type names accepted by ParseColumns along with the --key-type names
+var columnTypes = map[string]pb.KeyType{
+	"int":   pb.KeyType_KEY_INT64,
+	"uint":  pb.KeyType_KEY_UINT64,
+	"float": pb.KeyType_KEY_FLOAT64,
+}
+
+// ParseColumns parses a list of sort keys such as "col3:int:desc,col1:string:asc". Each key is
+// a 1-based field, with or without the col prefix, a type and an optional direction, asc by default.
+func ParseColumns(spec string) ([]*pb.SortKey, error) {
+	var columns []*pb.SortKey
+	for _, item := range strings.Split(spec, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("invalid sort key %q, expected column:type[:asc|desc]", item)
+		}
+		field, err := strconv.Atoi(strings.TrimPrefix(parts[0], "col"))
+		if err != nil || field < 1 {
+			return nil, fmt.Errorf("invalid sort key column %q, expected a 1-based field like col3", parts[0])
+		}
+		t, ok := columnTypes[parts[1]]
+		if !ok {
+			if t, err = Parse(parts[1]); err != nil {
+				return nil, fmt.Errorf("sort key %q: %w", item, err)
+			}
+		}
+		column := &pb.SortKey{Field: int32(field), Type: t}
+		if len(parts) == 3 {
+			switch parts[2] {
+			case "asc":
+			case "desc":
+				column.Descending = true
+			default:
+				return nil, fmt.Errorf("invalid sort key direction %q, expected asc or desc", parts[2])
+			}
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// appendComposite appends the composite key of line to dst
+func appendComposite(dst []byte, line []byte, columns []*pb.SortKey, delimiter string) ([]byte, error) {
+	for _, c := range columns {
+		field, err := ioformat.KeyField{Field: int(c.Field), Delimiter: delimiter}.Bytes(line)
+		if err != nil {
+			return nil, err
+		}
+		tag := byte(c.Type) << 1
+		if c.Descending {
+			tag |= descending
+		}
+		dst = append(dst, tag)
+		start := len(dst)
+		if dst, err = appendColumn(dst, c.Type, field); err != nil {
+			return nil, fmt.Errorf("field %d: %w", c.Field, err)
+		}
+		if c.Descending {
+			invert(dst[start:])
+		}
+	}
+	return dst, nil
+}
+
+// appendColumn appends the ascending encoding of a field of type t to dst
+func appendColumn(dst []byte, t pb.KeyType, field []byte) ([]byte, error) {
+	switch t {
+	case pb.KeyType_KEY_STRING:
+		for _, c := range field {
+			dst = append(dst, c)
+			if c == 0 {
+				dst = append(dst, 0xff)
+			}
+		}
+		return append(dst, 0, 1), nil
+	case pb.KeyType_KEY_BIGINT:
+		enc, err := ParseBigInt(string(bytes.TrimSpace(field)))
+		if err != nil {
+			return nil, err
+		}
+		mag := enc[1:]
+		if enc[0] == negative {
+			dst = append(dst, 0)
+		} else {
+			dst = append(dst, 1)
+		}
+		start := len(dst)
+		dst = binary.BigEndian.AppendUint32(dst, uint32(len(mag)))
+		dst = append(dst, mag...)
+		if enc[0] == negative {
+			invert(dst[start:])
+		}
+		return dst, nil
+	}
+	key, err := Numeric(t).ParseKey(string(bytes.TrimSpace(field)))
+	if err != nil {
+		return nil, err
+	}
+	return binary.BigEndian.AppendUint64(dst, uint64(key)^1<<63), nil
+}
+
+var errTruncated = errors.New("truncated composite key")
+
+func invert(b []byte) {
+	for i := range b {
+		b[i] = ^b[i]
+	}
+}
+
+// formatComposite returns the columns of a composite key as a tuple, strings quoted
+func formatComposite(key []byte) string {
+	var columns []string
+	for len(key) > 0 {
+		tag := key[0]
+		value := key[1:]
+		if tag&descending != 0 {
+			value = bytes.Clone(value)
+			invert(value)
+		}
+		s, n, err := decodeColumn(pb.KeyType(tag>>1), value)
+		if err != nil {
+			return fmt.Sprintf("invalid(%x)", key)
+		}
+		columns = append(columns, s)
+		key = key[1+n:]
+	}
+	return "(" + strings.Join(columns, ", ") + ")"
+}
+
+// decodeColumn returns the value of the ascending encoding at the start of b, and its length
+func decodeColumn(t pb.KeyType, b []byte) (string, int, error) {
+	switch t {
+	case pb.KeyType_KEY_STRING:
+		var s []byte
+		for i := 0; i+1 < len(b); i++ {
+			if b[i] != 0 {
+				s = append(s, b[i])
+				continue
+			}
+			i++
+			if b[i] == 1 {
+				return strconv.Quote(string(s)), i + 1, nil
+			}
+			s = append(s, 0)
+		}
+		return "", 0, errTruncated
+	case pb.KeyType_KEY_BIGINT:
+		if len(b) < 5 {
+			return "", 0, errTruncated
+		}
+		head := bytes.Clone(b[1:5])
+		if b[0] == 0 {
+			invert(head)
+		}
+		n := 5 + int(binary.BigEndian.Uint32(head))
+		if n > len(b) {
+			return "", 0, errTruncated
+		}
+		enc := append([]byte{0}, b[5:n]...)
+		if b[0] == 0 {
+			enc[0] = negative
+			invert(enc[1:])
+		}
+		return formatBigInt(enc), n, nil
+	}
+	if len(b) < 8 {
+		return "", 0, errTruncated
+	}
+	key := int64(binary.BigEndian.Uint64(b) ^ 1<<63)
+	return Numeric(t).FormatKey(key), 8, nil
+}
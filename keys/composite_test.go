@@ -0,0 +1,108 @@
+package keys
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"testing"
+
+	pb "mapreduce/proto"
+)
+
+func composite(t *testing.T, line string, columns []*pb.SortKey) []byte {
+	t.Helper()
+	key, err := appendComposite(nil, []byte(line), columns, ",")
+	if err != nil {
+		t.Fatalf("appendComposite(%q): %v", line, err)
+	}
+	return key
+}
+
+func sign(c int) int {
+	switch {
+	case c < 0:
+		return -1
+	case c > 0:
+		return 1
+	}
+	return 0
+}
+
+func TestCompositeStrings(t *testing.T) {
+	// 0x00 must sort before every other byte and after the end of a shorter string
+	values := []string{"", "\x00", "\x00\x00", "\x00\x01", "\x00\xff", "\x01", "a", "a\x00", "a\x00b", "a\x01", "ab", "b", "\xff", "\xff\x00"}
+	columns := []*pb.SortKey{{Field: 1, Type: pb.KeyType_KEY_STRING}}
+	for _, a := range values {
+		for _, b := range values {
+			got := sign(bytes.Compare(composite(t, a, columns), composite(t, b, columns)))
+			if want := bytes.Compare([]byte(a), []byte(b)); got != want {
+				t.Errorf("compare(%q, %q) = %d, want %d", a, b, got, want)
+			}
+		}
+	}
+}
+
+func TestCompositePrefix(t *testing.T) {
+	// a string column ends where its value does, so the next column only breaks ties
+	columns := []*pb.SortKey{{Field: 1, Type: pb.KeyType_KEY_STRING}, {Field: 2, Type: pb.KeyType_KEY_INT64}}
+	ordered := []string{"a,9", "a\x00,1", "ab,-5", "ab,3", "abc,-9"}
+	for i := 1; i < len(ordered); i++ {
+		a, b := composite(t, ordered[i-1], columns), composite(t, ordered[i], columns)
+		if bytes.Compare(a, b) >= 0 {
+			t.Errorf("%q does not sort before %q", ordered[i-1], ordered[i])
+		}
+	}
+}
+
+func TestCompositeDescending(t *testing.T) {
+	columns := []*pb.SortKey{
+		{Field: 1, Type: pb.KeyType_KEY_INT64, Descending: true},
+		{Field: 2, Type: pb.KeyType_KEY_STRING, Descending: true},
+		{Field: 3, Type: pb.KeyType_KEY_FLOAT64},
+	}
+	ordered := []string{"9223372036854775807,a,0", "3,b,-1", "3,b,2.5", "3,ab,-Inf", "3,a,0", "3,,0", "-1,z,0", "-9223372036854775808,z,0"}
+	for i := 1; i < len(ordered); i++ {
+		a, b := composite(t, ordered[i-1], columns), composite(t, ordered[i], columns)
+		if bytes.Compare(a, b) >= 0 {
+			t.Errorf("%q does not sort before %q", ordered[i-1], ordered[i])
+		}
+	}
+	if got, want := formatComposite(composite(t, "3,ab,-Inf", columns)), `(3, "ab", -Inf)`; got != want {
+		t.Errorf("formatComposite = %s, want %s", got, want)
+	}
+}
+
+func TestCompositeBigInt(t *testing.T) {
+	// the length prefix orders magnitudes of different sizes, and is inverted with negative ones
+	values := []string{"-100000000000000000000000", "-256", "-255", "-10", "-9", "-1", "0", "1", "9", "10", "255", "256", "100000000000000000000000"}
+	for _, desc := range []bool{false, true} {
+		columns := []*pb.SortKey{
+			{Field: 1, Type: pb.KeyType_KEY_BIGINT, Descending: desc},
+			{Field: 2, Type: pb.KeyType_KEY_STRING},
+		}
+		for _, a := range values {
+			for _, b := range values {
+				x, _ := new(big.Int).SetString(a, 10)
+				y, _ := new(big.Int).SetString(b, 10)
+				want := x.Cmp(y)
+				if desc {
+					want = -want
+				}
+				if want == 0 {
+					continue
+				}
+				// the second column must not change the order of different numbers
+				got := sign(bytes.Compare(composite(t, a+",z", columns), composite(t, b+",a", columns)))
+				if got != want {
+					t.Errorf("desc=%v: compare(%s, %s) = %d, want %d", desc, a, b, got, want)
+				}
+			}
+		}
+		for _, v := range values {
+			got := formatComposite(composite(t, v+",x", columns))
+			if want := fmt.Sprintf(`(%s, "x")`, v); got != want {
+				t.Errorf("desc=%v: formatComposite = %s, want %s", desc, got, want)
+			}
+		}
+	}
+}
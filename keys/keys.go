@@ -0,0 +1,168 @@
+// Package keys builds records from text lines and orders them by the key type of the job.
+package keys
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"mapreduce/ioformat"
+	pb "mapreduce/proto"
+)
+
+// names maps the --key-type names to their protocol value
+var names = map[string]pb.KeyType{
+	"int64":   pb.KeyType_KEY_INT64,
+	"uint64":  pb.KeyType_KEY_UINT64,
+	"float64": pb.KeyType_KEY_FLOAT64,
+	"string":  pb.KeyType_KEY_STRING,
+	"bigint":  pb.KeyType_KEY_BIGINT,
+}
+
+// Parse returns the key type named name.
+func Parse(name string) (pb.KeyType, error) {
+	t, ok := names[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown key type %q, expected int64, uint64, float64, string or bigint", name)
+	}
+	return t, nil
+}
+
+// Name returns the --key-type name of t.
+func Name(t pb.KeyType) string {
+	for name, v := range names {
+		if v == t {
+			return name
+		}
+	}
+	return t.String()
+}
+
+// Bytes reports whether records of type t are keyed by Record.KeyBytes rather than Record.Key.
+func Bytes(t pb.KeyType) bool {
+	return t == pb.KeyType_KEY_STRING || t == pb.KeyType_KEY_BIGINT || t == pb.KeyType_KEY_COMPOSITE
+}
+
+// Numeric returns the type of the values of t, whose int64 keys order like them.
+// It is ioformat.Int64 for byte keys, which have no int64 key.
+func Numeric(t pb.KeyType) ioformat.Type {
+	switch t {
+	case pb.KeyType_KEY_UINT64:
+		return ioformat.Uint64
+	case pb.KeyType_KEY_FLOAT64:
+		return ioformat.Float64
+	}
+	return ioformat.Int64
+}
+
+// CompareBytes returns the order of the byte keys of type t.
+func CompareBytes(t pb.KeyType) func(a, b []byte) int {
+	if t == pb.KeyType_KEY_BIGINT {
+		return compareBigInt
+	}
+	return bytes.Compare
+}
+
+// Line returns the line of r, written back to the output. Records without payload are a key
+// read from a whole line, written back as it was read, or in decimal for big integers.
+func Line(t pb.KeyType, r *pb.Record) []byte {
+	if len(r.Payload) > 0 {
+		return r.Payload
+	}
+	if t == pb.KeyType_KEY_BIGINT {
+		return []byte(formatBigInt(r.KeyBytes))
+	}
+	return r.KeyBytes
+}
+
+// Format returns the key of r as text, for logs and reports.
+func Format(t pb.KeyType, r *pb.Record) string {
+	switch t {
+	case pb.KeyType_KEY_BIGINT:
+		return formatBigInt(r.KeyBytes)
+	case pb.KeyType_KEY_STRING:
+		return string(r.KeyBytes)
+	case pb.KeyType_KEY_COMPOSITE:
+		return formatComposite(r.KeyBytes)
+	}
+	return Numeric(t).FormatKey(r.Key)
+}
+
+// Parser builds records from text lines.
+type Parser struct {
+	Field ioformat.KeyField
+	Type  pb.KeyType
+	// Columns of composite keys, split by Field.Delimiter
+	Columns []*pb.SortKey
+}
+
+// Records reports whether lines are sorted as records written back whole, rather than as int64 values.
+func (p Parser) Records() bool {
+	return p.Field.Field > 0 || Bytes(p.Type)
+}
+
+// Record parses the key of line. A record keyed by the whole line only holds the key, the line itself
+// for strings, and a numeric value parsed from the whole line has neither payload nor byte key.
+func (p Parser) Record(line []byte) (*pb.Record, error) {
+	if p.Type == pb.KeyType_KEY_COMPOSITE {
+		key, err := appendComposite(nil, line, p.Columns, p.Field.Delimiter)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.Record{KeyBytes: key, Payload: line}, nil
+	}
+	if Bytes(p.Type) {
+		key, err := p.Field.Bytes(line)
+		if err != nil {
+			return nil, err
+		}
+		if p.Type == pb.KeyType_KEY_BIGINT {
+			if key, err = ParseBigInt(string(bytes.TrimSpace(key))); err != nil {
+				return nil, err
+			}
+		}
+		if p.Field.Field == 0 {
+			return &pb.Record{KeyBytes: key}, nil
+		}
+		return &pb.Record{KeyBytes: key, Payload: line}, nil
+	}
+	field, err := p.Field.Bytes(line)
+	if err != nil {
+		return nil, err
+	}
+	key, err := Numeric(p.Type).ParseKey(string(bytes.TrimSpace(field)))
+	if err != nil {
+		return nil, err
+	}
+	if p.Field.Field == 0 {
+		return &pb.Record{Key: key}, nil
+	}
+	return &pb.Record{Key: key, Payload: line}, nil
+}
+
+// Reader reads text records, one per non-blank line.
+type Reader struct {
+	lr *ioformat.LineReader
+	p  Parser
+	// Seq is the seq of the next record, incremented by each read
+	Seq int64
+}
+
+func NewReader(r io.Reader, p Parser) *Reader {
+	return &Reader{lr: ioformat.NewLineReader(r), p: p}
+}
+
+// Read returns the next record, or io.EOF after the last one.
+func (r *Reader) Read() (*pb.Record, error) {
+	line, err := r.lr.Read()
+	if err != nil {
+		return nil, err
+	}
+	rec, err := r.p.Record(line)
+	if err != nil {
+		return nil, fmt.Errorf("line %d: %w", r.lr.Line(), err)
+	}
+	rec.Seq = r.Seq
+	r.Seq++
+	return rec, nil
+}
@@ -0,0 +1,149 @@
+package keys
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	pb "mapreduce/proto"
+)
+
+// Comparator orders string keys. It returns a negative number when a sorts before b, a positive
+// number when it sorts after, and 0 only for keys that sort together, and must be a total order.
+type Comparator func(a, b []byte) int
+
+var (
+	comparatorsMu sync.RWMutex
+	comparators   = map[string]Comparator{
+		"bytes":    bytes.Compare,
+		"casefold": compareFold,
+	}
+)
+
+// Register makes a comparator available to jobs under name, the --comparator flag. The master and
+// every worker of a job must register the same comparators, typically from an init function of the
+// program embedding them. It panics if name is already registered or c is nil.
+func Register(name string, c Comparator) {
+	comparatorsMu.Lock()
+	defer comparatorsMu.Unlock()
+	if c == nil {
+		panic("keys: Register comparator is nil")
+	}
+	if _, dup := comparators[name]; dup {
+		panic("keys: Register called twice for comparator " + name)
+	}
+	comparators[name] = c
+}
+
+// lookup returns the comparator registered as name
+func lookup(name string) (Comparator, error) {
+	comparatorsMu.RLock()
+	defer comparatorsMu.RUnlock()
+	c, ok := comparators[name]
+	if !ok {
+		names := make([]string, 0, len(comparators))
+		for name := range comparators {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown comparator %q, registered: %s", name, strings.Join(names, ", "))
+	}
+	return c, nil
+}
+
+// compareFold orders ASCII letters regardless of case, then by bytes so that the order is total
+func compareFold(a, b []byte) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := cmp.Compare(lower(a[i]), lower(b[i])); c != 0 {
+			return c
+		}
+	}
+	if c := cmp.Compare(len(a), len(b)); c != 0 {
+		return c
+	}
+	return bytes.Compare(a, b)
+}
+
+func lower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// Order is the order of the keys of a job: the order of their type, or of a registered comparator
+// for string keys, ascending or descending. Reducer intervals always follow the ascending order,
+// a descending job gives them to the reducers in reverse, so that the first part holds the largest keys.
+type Order struct {
+	Type pb.KeyType
+	// Comparator is the name of a registered comparator of string keys, the byte order when empty
+	Comparator string
+	Descending bool
+}
+
+// Validate checks that the comparator is registered and applies to the key type.
+func (o Order) Validate() error {
+	if o.Comparator == "" {
+		return nil
+	}
+	if o.Type != pb.KeyType_KEY_STRING {
+		return fmt.Errorf("comparator %q orders string keys, not %s keys", o.Comparator, Name(o.Type))
+	}
+	_, err := lookup(o.Comparator)
+	return err
+}
+
+// CompareBytes returns the ascending order of the byte keys, which bounds the reducer intervals.
+// The order must be valid.
+func (o Order) CompareBytes() func(a, b []byte) int {
+	if o.Comparator != "" {
+		c, _ := lookup(o.Comparator)
+		return c
+	}
+	return CompareBytes(o.Type)
+}
+
+// Compare returns the order of the records in the output. Records with equal keys are ordered by
+// their position in the input, in both directions. The order must be valid.
+func (o Order) Compare() func(a, b *pb.Record) int {
+	compare := o.compareKeys()
+	descending := o.Descending
+	return func(a, b *pb.Record) int {
+		c := compare(a, b)
+		if descending {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Seq, b.Seq)
+	}
+}
+
+// Equal returns whether two records have equal keys, so that a unique job keeps only one of them.
+// The order must be valid.
+func (o Order) Equal() func(a, b *pb.Record) bool {
+	compare := o.compareKeys()
+	return func(a, b *pb.Record) bool { return compare(a, b) == 0 }
+}
+
+// compareKeys returns the ascending order of the keys of records
+func (o Order) compareKeys() func(a, b *pb.Record) int {
+	if Bytes(o.Type) {
+		compareBytes := o.CompareBytes()
+		return func(a, b *pb.Record) int { return compareBytes(a.KeyBytes, b.KeyBytes) }
+	}
+	return func(a, b *pb.Record) int { return cmp.Compare(a.Key, b.Key) }
+}
+
+// Bounds reports whether the i-th of n reducer intervals is the lowest one, which has no lower bound,
+// and whether it is the highest one, which has no upper bound.
+func (o Order) Bounds(i, n int) (lowest, highest bool) {
+	if o.Descending {
+		return i == n-1, i == 0
+	}
+	return i == 0, i == n-1
+}
@@ -0,0 +1,29 @@
+package keys
+
+import "testing"
+
+func TestBounds(t *testing.T) {
+	tests := []struct {
+		descending bool
+		i, n       int
+		lowest     bool
+		highest    bool
+	}{
+		{false, 0, 1, true, true},
+		{true, 0, 1, true, true},
+		{false, 0, 3, true, false},
+		{false, 1, 3, false, false},
+		{false, 2, 3, false, true},
+		// descending jobs give the highest interval to the first reducer
+		{true, 0, 3, false, true},
+		{true, 1, 3, false, false},
+		{true, 2, 3, true, false},
+	}
+	for _, tt := range tests {
+		lowest, highest := Order{Descending: tt.descending}.Bounds(tt.i, tt.n)
+		if lowest != tt.lowest || highest != tt.highest {
+			t.Errorf("descending %v Bounds(%d, %d) = %v, %v, want %v, %v",
+				tt.descending, tt.i, tt.n, lowest, highest, tt.lowest, tt.highest)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// maxPreview is how many values Preview shows before truncating.
+const maxPreview = 16
+
+// output serializes log records with the terminal progress view, see Around.
+var output = &terminalWriter{w: os.Stderr}
+
+type terminalWriter struct {
+	mu            sync.Mutex
+	w             io.Writer
+	before, after func()
+}
+
+func (t *terminalWriter) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.before != nil {
+		t.before()
+	}
+	n, err := t.w.Write(p)
+	if t.after != nil {
+		t.after()
+	}
+	return n, err
+}
+
+// Around registers functions called before and after each log record is written,
+// so that a view redrawn in place on the terminal can step aside. Pass nil to remove them.
+func Around(before, after func()) {
+	output.mu.Lock()
+	defer output.mu.Unlock()
+	output.before, output.after = before, after
+}
+
+// Exclusive runs fn while no log record is being written.
+func Exclusive(fn func()) {
+	output.mu.Lock()
+	defer output.mu.Unlock()
+	fn()
+}
+
+// Setup installs the default slog logger writing to stderr, so that stdout stays free for data.
+func Setup(level, format string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+	var handler slog.Handler
+	switch format {
+	case "text":
+		handler = slog.NewTextHandler(output, opts)
+	case "json":
+		handler = slog.NewJSONHandler(output, opts)
+	default:
+		return fmt.Errorf("invalid log format %q, expected json or text", format)
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// Preview logs at most maxPreview values followed by the number of omitted ones.
+// It is only formatted if the record is actually emitted.
+type Preview []int64
+
+func (p Preview) LogValue() slog.Value {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, v := range p {
+		if i == maxPreview {
+			fmt.Fprintf(&b, " ... %d more", len(p)-maxPreview)
+			break
+		}
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprint(&b, v)
+	}
+	b.WriteByte(']')
+	return slog.StringValue(b.String())
+}
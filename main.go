@@ -3,13 +3,18 @@ package main
 import (
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 	"os"
 	"os/signal"
-	"time"
 
+	"mapreduce/auth"
+	"mapreduce/ioformat"
+	"mapreduce/keys"
+	"mapreduce/logging"
 	"mapreduce/master"
+	"mapreduce/metrics"
+	"mapreduce/tracing"
 	"mapreduce/worker"
 
 	"google.golang.org/grpc"
@@ -21,56 +26,187 @@ func main() {
 	var port string
 	var configPath string
 	var inputPath string
+	var secretPath string
+	var metricsAddr string
+	var logLevel string
+	var logFormat string
+	var traceDir string
+	var outputPath string
+	var dashboardAddr string
+	var outputMode string
+	var inputFormat string
+	var outputFormat string
+	var count int
+	var sortThreads int
+	var sharedInput bool
+	var keyField int
+	var delimiter string
+	var keyType string
+	var sortKeys string
+	var order string
+	var comparator string
+	var unique bool
+	var job string
+	var quantiles string
 	flag.StringVar(&mode, "mode", "master", "Mode to run: master or worker")
 	flag.StringVar(&port, "port", ":50051", "Worker listen port (only used in worker mode)")
 	flag.StringVar(&configPath, "config", "config.yaml", "Path to configuration file (only used in master mode)")
-	flag.StringVar(&inputPath, "input", "input", "Path to input file (only used in master mode)")
+	flag.StringVar(&inputPath, "input", "input", "Input files: comma-separated list of files, directories and glob patterns (only used in master mode)")
+	flag.StringVar(&secretPath, "auth-secret-file", "", "Path to the shared authentication secret (defaults to $"+auth.SecretEnv+", auth disabled if neither is set)")
+	flag.StringVar(&metricsAddr, "metrics-addr", "", "Address to serve Prometheus /metrics on, e.g. :9100 (disabled if empty)")
+	flag.StringVar(&logLevel, "log-level", "info", "Minimum log level: debug, info, warn or error")
+	flag.StringVar(&logFormat, "log-format", "text", "Log format: json or text")
+	flag.StringVar(&traceDir, "trace-dir", "", "Directory to write Chrome trace-event files to (tracing disabled if empty)")
+	flag.StringVar(&outputPath, "output", "", "Merged trace file (merge-traces mode, default trace.json), generated file (generate mode, default input) or - to write the sorted output to stdout (master mode)")
+	flag.StringVar(&dashboardAddr, "dashboard-addr", "", "Address to serve the job dashboard on, e.g. :8080 (only used in master mode, disabled if empty)")
+	flag.StringVar(&outputMode, "output-mode", "", "Output mode: parts (one file per reducer, the default) or merged (a single file written by the master)")
+	flag.StringVar(&inputFormat, "input-format", "auto", "Input format: text, binary-le, binary-be or auto (only used in master mode)")
+	flag.StringVar(&outputFormat, "output-format", "text", "Output format: text, binary-le or binary-be (master and generate modes)")
+	flag.IntVar(&count, "count", 1000000, "Number of values to generate (only used in generate mode)")
+	flag.IntVar(&sortThreads, "sort-threads", 0, "Goroutines used to sort (only used in worker mode, 0 means GOMAXPROCS)")
+	flag.BoolVar(&sharedInput, "shared-input", false, "Mappers read their split of the input from the same path instead of receiving it from the master (only used in master mode)")
+	flag.IntVar(&keyField, "key-field", 0, "Sort text lines as records by this 1-based field and write the whole lines back (only used in master mode, 0 sorts values)")
+	flag.StringVar(&delimiter, "delimiter", ",", "Field delimiter of records (only used with --key-field and --sort-keys)")
+	flag.StringVar(&keyType, "key-type", "int64", "Type of the values or sort keys: int64, uint64, float64, string, which sorts lines or key fields in byte order like LC_ALL=C sort, or bigint (master and generate modes)")
+	flag.StringVar(&sortKeys, "sort-keys", "", "Sort text lines by a composite key of fields, types and directions, e.g. col3:int:desc,col1:string:asc, split by --delimiter (only used in master mode)")
+	flag.StringVar(&order, "order", "asc", "Sort order: asc or desc (only used in master mode)")
+	flag.StringVar(&comparator, "comparator", "", "Name of a registered comparator ordering string keys, e.g. casefold (only used in master mode, byte order if empty)")
+	flag.BoolVar(&unique, "unique", false, "Drop duplicates like sort -u, keeping the first line of each key (only used in master mode)")
+	flag.StringVar(&job, "job", "sort", "What the job computes: sort, histogram, a line per distinct value with its count, or quantiles (only used in master mode)")
+	flag.StringVar(&quantiles, "quantiles", "0.5,0.99,0.999", "Quantiles between 0 and 1 printed by --job=quantiles, comma-separated")
 	flag.Parse()
 
+	if err := logging.Setup(logLevel, logFormat); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if metricsAddr != "" {
+		go func() {
+			if err := metrics.Serve(metricsAddr); err != nil {
+				slog.Error("Metrics server stopped", "addr", metricsAddr, "error", err)
+			}
+		}()
+	}
+
+	secret, err := auth.LoadSecret(secretPath)
+	if err != nil {
+		slog.Error("Failed to load auth secret", "error", err)
+		os.Exit(1)
+	}
+
 	switch mode {
 	case "master":
 		if configPath == "" || inputPath == "" {
 			fmt.Println("Usage: go run main.go --mode=master --config=config.yaml --input=input")
 			return
 		}
-		master.RunMaster(configPath, inputPath)
+		tracing.Configure(traceDir, "master")
+		if dashboardAddr != "" {
+			go func() {
+				if err := master.ServeDashboard(dashboardAddr); err != nil {
+					slog.Error("Dashboard server stopped", "addr", dashboardAddr, "error", err)
+				}
+			}()
+		}
+		master.RunMaster(configPath, inputPath, master.Options{
+			Secret:       secret,
+			OutputMode:   outputMode,
+			InputFormat:  inputFormat,
+			OutputFormat: outputFormat,
+			SharedInput:  sharedInput,
+			Output:       outputPath,
+			KeyField:     keyField,
+			Delimiter:    delimiter,
+			KeyType:      keyType,
+			SortKeys:     sortKeys,
+			Order:        order,
+			Comparator:   comparator,
+			Unique:       unique,
+			Job:          job,
+			Quantiles:    quantiles,
+		})
 	case "worker":
 		if port == "" {
 			fmt.Println("Usage: go run main.go --mode=worker --port=:50051")
 			return
 		}
-		runWorker(port)
+		tracing.Configure(traceDir, "worker"+port)
+		runWorker(port, secret, sortThreads)
+	case "merge-traces":
+		if flag.NArg() == 0 {
+			fmt.Println("Usage: go run main.go --mode=merge-traces --output=trace.json traces/trace-<job>-*.json")
+			return
+		}
+		if outputPath == "" {
+			outputPath = "trace.json"
+		}
+		if err := tracing.Merge(outputPath, flag.Args()); err != nil {
+			slog.Error("Failed to merge traces", "error", err)
+			os.Exit(1)
+		}
+	case "generate":
+		if outputPath == "" {
+			outputPath = "input"
+		}
+		format, err := ioformat.Parse(outputFormat)
+		t, typeErr := keys.Parse(keyType)
+		if err != nil || format == ioformat.Auto || typeErr != nil || keys.Bytes(t) {
+			fmt.Println("Usage: go run main.go --mode=generate --count=1000000 --output=input --output-format=text|binary-le|binary-be --key-type=int64|uint64|float64")
+			return
+		}
+		if err := generateInput(outputPath, count, format, keys.Numeric(t)); err != nil {
+			slog.Error("Failed to generate input", "path", outputPath, "error", err)
+			os.Exit(1)
+		}
 	default:
-		log.Fatalf("Unknown mode: %s "+
+		fmt.Fprintf(os.Stderr, "Unknown mode: %s "+
 			"\nUsage"+
 			"\nmaster: go run main.go --mode=master --config=config.yaml --input=input"+
-			"\nworker: go run main.go --mode=worker --port=:50051", mode)
+			"\nworker: go run main.go --mode=worker --port=:50051"+
+			"\nmerge-traces: go run main.go --mode=merge-traces --output=trace.json traces/*.json"+
+			"\ngenerate: go run main.go --mode=generate --count=1000000 --output=input --output-format=text\n", mode)
+		os.Exit(2)
 
 	}
 }
 
-func runWorker(port string) {
+func runWorker(port string, secret []byte, sortThreads int) {
 	ws := &worker.WorkerServer{}
 	ws.BindAddress = port
+	ws.SortThreads = sortThreads
 
 	lis, err := net.Listen("tcp", port)
 	if err != nil {
-		log.Fatalf("failed to listen on %s: %v", port, err)
+		slog.Error("Failed to listen", "port", port, "error", err)
+		os.Exit(1)
 	}
 
-	grpcServer := grpc.NewServer()
+	interceptors := []grpc.UnaryServerInterceptor{metrics.UnaryServerInterceptor(), tracing.UnaryServerInterceptor()}
+	streamInterceptors := []grpc.StreamServerInterceptor{metrics.StreamServerInterceptor(), tracing.StreamServerInterceptor()}
+	if secret != nil {
+		authority := auth.NewAuthority(secret)
+		interceptors = append(interceptors, authority.UnaryServerInterceptor(worker.MethodRoles, ws.JobID))
+		streamInterceptors = append(streamInterceptors, authority.StreamServerInterceptor(worker.MethodRoles, ws.JobID))
+	}
+	grpcServer := grpc.NewServer(
+		grpc.MaxRecvMsgSize(worker.MaxMessageSize),
+		grpc.ChainUnaryInterceptor(interceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	)
 	pb.RegisterWorkerServiceServer(grpcServer, ws)
 
 	go func() {
-		fmt.Printf("%s Worker listening on port %s\n", time.Now().Format("2006/01/02 15:04:05"), port[1:])
+		slog.Info("Worker listening", "worker", port)
 		if err := grpcServer.Serve(lis); err != nil {
-			log.Fatalf("Failed to serve gRPC: %v", err)
+			slog.Error("Failed to serve gRPC", "worker", port, "error", err)
+			os.Exit(1)
 		}
 	}()
 
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
 	<-c
-	fmt.Println("Received shutdown signal, shutting down...")
+	slog.Info("Received shutdown signal, shutting down", "worker", port)
 	grpcServer.GracefulStop()
 }
@@ -0,0 +1,151 @@
+package master
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	pb "mapreduce/proto"
+)
+
+//go:embed dashboard.html
+var dashboardHTML []byte
+
+// workerView is what the dashboard shows about one worker from config.yaml
+type workerView struct {
+	Address  string    `json:"address"`
+	Role     string    `json:"role"`
+	Alive    bool      `json:"alive"`
+	LastSeen time.Time `json:"last_seen"`
+
+	// Mapper
+	ChunkValues         int64            `json:"chunk_values"`
+	ValuesSent          int64            `json:"values_sent"`
+	ValuesSentByReducer map[string]int64 `json:"values_sent_by_reducer,omitempty"`
+	BytesSentByReducer  map[string]int64 `json:"bytes_sent_by_reducer,omitempty"`
+
+	// Reducer
+	IntervalStart  string `json:"interval_start"`
+	IntervalEnd    string `json:"interval_end"`
+	MappersPending int32  `json:"mappers_pending"`
+	ValuesWritten  int64  `json:"values_written"`
+	BytesWritten   int64  `json:"bytes_written"`
+
+	ValuesReceived int64 `json:"values_received"`
+	Done           bool  `json:"done"`
+}
+
+// jobView is the state of the job served to the dashboard as JSON
+type jobView struct {
+	JobID       string        `json:"job_id"`
+	Phase       string        `json:"phase"`
+	Started     time.Time     `json:"started"`
+	TotalValues int64         `json:"total_values"`
+	Workers     []*workerView `json:"workers"`
+}
+
+// dashboard holds the job state, updated by RunMaster and the progress poller
+type dashboard struct {
+	mu     sync.Mutex
+	job    jobView
+	byAddr map[string]*workerView
+}
+
+var dash = &dashboard{byAddr: map[string]*workerView{}}
+
+func (d *dashboard) start(jobID string, cfg *Config) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.job = jobView{JobID: jobID, Phase: "starting", Started: time.Now()}
+	d.byAddr = map[string]*workerView{}
+	for i, addr := range cfg.Workers {
+		role := "reducer"
+		if i < cfg.Mappers {
+			role = "mapper"
+		}
+		w := &workerView{Address: addr, Role: role}
+		d.job.Workers = append(d.job.Workers, w)
+		d.byAddr[addr] = w
+	}
+}
+
+func (d *dashboard) setPhase(phase string) {
+	d.mu.Lock()
+	d.job.Phase = phase
+	d.mu.Unlock()
+}
+
+func (d *dashboard) setTotal(total int64) {
+	d.mu.Lock()
+	d.job.TotalValues = total
+	d.mu.Unlock()
+}
+
+func (d *dashboard) setInterval(addr string, start, end string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if w, ok := d.byAddr[addr]; ok {
+		w.IntervalStart, w.IntervalEnd = start, end
+	}
+}
+
+func (d *dashboard) setChunk(addr string, values int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if w, ok := d.byAddr[addr]; ok {
+		w.ChunkValues += values
+	}
+}
+
+// update records the statuses polled from addrs, a nil status marks the worker as unreachable
+func (d *dashboard) update(addrs []string, statuses []*pb.GetStatusResponse, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, addr := range addrs {
+		w, ok := d.byAddr[addr]
+		if !ok {
+			continue
+		}
+		s := statuses[i]
+		w.Alive = s != nil
+		if s == nil || s.JobId != d.job.JobID {
+			continue
+		}
+		w.LastSeen = now
+		w.ValuesReceived = s.ValuesReceived
+		w.ValuesSent = s.ValuesSent
+		w.ValuesSentByReducer = s.ValuesSentByReducer
+		w.BytesSentByReducer = s.BytesSentByReducer
+		w.MappersPending = s.MappersPending
+		w.ValuesWritten = s.ValuesWritten
+		w.BytesWritten = s.BytesWritten
+		w.Done = s.Done
+	}
+}
+
+func (d *dashboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(dashboardHTML)
+	case "/api/job":
+		d.mu.Lock()
+		data, err := json.Marshal(d.job)
+		d.mu.Unlock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// ServeDashboard serves the job dashboard on addr. It blocks like http.ListenAndServe.
+func ServeDashboard(addr string) error {
+	return http.ListenAndServe(addr, dash)
+}
@@ -0,0 +1,34 @@
+package master
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// fanOut calls fn for 0 <= i < n with at most limit calls running at once, each with its own
+// deadline after timeout. Every call runs even if others fail, and all their errors are returned joined.
+func fanOut(ctx context.Context, n, limit int, timeout time.Duration, fn func(ctx context.Context, i int) error) error {
+	if limit <= 0 || limit > n {
+		limit = n
+	}
+	sem := make(chan struct{}, limit)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			callCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			errs[i] = fn(callCtx, i)
+		}(i)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
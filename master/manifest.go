@@ -0,0 +1,180 @@
+package master
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"mapreduce/ioformat"
+	"mapreduce/keys"
+	pb "mapreduce/proto"
+	"mapreduce/worker"
+)
+
+const (
+	manifestName = "_manifest.json"
+	successName  = "_SUCCESS"
+)
+
+// Manifest describes the output of a job, the parts are listed in global order.
+// In merged output mode the parts are the consecutive sections of the merged file.
+type Manifest struct {
+	JobID   string `json:"job_id"`
+	Records int64  `json:"records"`
+	// input records and dropped duplicates of unique jobs, whose records are the distinct ones
+	InputRecords *int64         `json:"input_records,omitempty"`
+	Duplicates   *int64         `json:"duplicates,omitempty"`
+	Merged       *ManifestFile  `json:"merged,omitempty"`
+	Parts        []ManifestPart `json:"parts"`
+}
+
+type ManifestFile struct {
+	File    string `json:"file"`
+	Records int64  `json:"records"`
+	// values counted by the lines of a histogram
+	Values   int64  `json:"values,omitempty"`
+	Bytes    int64  `json:"bytes"`
+	Checksum string `json:"checksum"`
+}
+
+type ManifestPart struct {
+	File          string `json:"file,omitempty"`
+	Reducer       string `json:"reducer"`
+	IntervalStart *int64 `json:"interval_start,omitempty"`
+	IntervalEnd   *int64 `json:"interval_end,omitempty"`
+	// interval of keys other than int64, a missing bound is unbounded
+	KeyStart *string `json:"key_start,omitempty"`
+	KeyEnd   *string `json:"key_end,omitempty"`
+	Records  int64   `json:"records"`
+	Values   *int64  `json:"values,omitempty"`
+	Min      *int64  `json:"min,omitempty"`
+	Max      *int64  `json:"max,omitempty"`
+	MinKey   *string `json:"min_key,omitempty"`
+	MaxKey   *string `json:"max_key,omitempty"`
+	Bytes    int64   `json:"bytes,omitempty"`
+	Checksum string  `json:"checksum,omitempty"`
+}
+
+// outputPatterns match the files written by jobs in the output directory, along with their temporary files
+var outputPatterns = []string{"part-*", mergedName + "*", ".part-*.inprogress", "." + mergedName + "*.inprogress"}
+
+// clearOutput removes the markers, then the output files of earlier jobs from dir, so that parts of
+// fewer reducers, of another compression or another output mode are not read along with this job's.
+// Other files are left alone.
+func clearOutput(dir string) error {
+	for _, name := range []string{successName, manifestName} {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	for _, pattern := range outputPatterns {
+		paths, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeManifest writes _manifest.json from the parts reported by the reducers, and
+// _SUCCESS once every part is present and the parts account for every input value.
+// merged is nil unless the reducers' output was merged into a single file. The workers of a unique
+// job dropped duplicates of the total input values, the others none. The parts of a histogram hold
+// a line per distinct value, whose counts add up to the total.
+func writeManifest(cfg *Config, reducerInfos []*pb.ReducerInfo, order keys.Order, reducers []*pb.GetStatusResponse, total int64, unique bool, duplicates int64, job pb.JobKind, merged *ManifestFile) error {
+	dir := cfg.OutputDir
+	m := Manifest{JobID: jobID, Merged: merged}
+	if unique || job == pb.JobKind_JOB_HISTOGRAM {
+		m.InputRecords = &total
+	}
+	if unique {
+		m.Duplicates = &duplicates
+	}
+	var counted int64
+	var missing []string
+	// int64 bounds are reported as numbers, the bounds of other key types as text
+	keyType := order.Type
+	intKeys := keyType == pb.KeyType_KEY_INT64
+	for i, ri := range reducerInfos {
+		part := ManifestPart{Reducer: ri.Address}
+		if intKeys {
+			part.IntervalStart, part.IntervalEnd = &ri.IntervalStart, &ri.IntervalEnd
+		} else {
+			lowest, highest := order.Bounds(i, len(reducerInfos))
+			start, end := formatInterval(ri, keyType, lowest, highest)
+			if !lowest {
+				part.KeyStart = &start
+			}
+			if !highest {
+				part.KeyEnd = &end
+			}
+		}
+		if merged == nil {
+			part.File = worker.PartName(int32(i))
+			if cfg.Compression.Output {
+				part.File += ioformat.GzipSuffix
+			}
+		}
+		if s := reducers[i]; s != nil && s.Part != nil {
+			part.Records = s.Part.Records
+			if job == pb.JobKind_JOB_HISTOGRAM {
+				part.Values = &s.Part.Values
+				counted += s.Part.Values
+			}
+			if merged == nil {
+				part.Bytes = s.Part.Bytes
+				part.Checksum = fmt.Sprintf("crc32c:%08x", s.Part.Crc32C)
+			}
+			if s.Part.Records > 0 && intKeys {
+				part.Min, part.Max = &s.Part.Min, &s.Part.Max
+			} else if s.Part.Records > 0 {
+				lo := keys.Format(keyType, &pb.Record{Key: s.Part.Min, KeyBytes: s.Part.MinKey})
+				hi := keys.Format(keyType, &pb.Record{Key: s.Part.Max, KeyBytes: s.Part.MaxKey})
+				part.MinKey, part.MaxKey = &lo, &hi
+			}
+		} else {
+			missing = append(missing, part.File)
+		}
+		m.Records += part.Records
+		m.Parts = append(m.Parts, part)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, manifestName), data, 0o644); err != nil {
+		return err
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("parts not reported by reducers: %v", missing)
+	}
+	if job == pb.JobKind_JOB_HISTOGRAM {
+		if counted != total {
+			return fmt.Errorf("parts count %d values, input had %d", counted, total)
+		}
+		if merged != nil && merged.Values != total {
+			return fmt.Errorf("merged file counts %d values, input had %d", merged.Values, total)
+		}
+		return os.WriteFile(filepath.Join(dir, successName), nil, 0o644)
+	}
+	expected := total - duplicates
+	if m.Records != expected {
+		return fmt.Errorf("parts hold %d records, expected %d of %d input records", m.Records, expected, total)
+	}
+	if merged != nil && merged.Records != expected {
+		return fmt.Errorf("merged file holds %d records, expected %d of %d input records", merged.Records, expected, total)
+	}
+	return os.WriteFile(filepath.Join(dir, successName), nil, 0o644)
+}
@@ -1,25 +1,144 @@
 package master
 
 import (
+	"bufio"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
 	"gopkg.in/yaml.v3"
-	"log"
+	"io"
+	"log/slog"
+	"mapreduce/auth"
+	"mapreduce/ioformat"
+	"mapreduce/keys"
+	"mapreduce/logging"
+	"mapreduce/metrics"
 	pb "mapreduce/proto"
+	"mapreduce/tracing"
 	"math"
-	"math/rand"
+	mathrand "math/rand"
 	"os"
-	"sort"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
+// the master token is only valid for the duration of a job
+const tokenTTL = 12 * time.Hour
+
+// authority signs the tokens sent to workers, nil when authentication is disabled
+var authority *auth.Authority
+
+// jobID identifies this run in the logs of the master and the workers
+var jobID string
+var masterToken string
+
+// logger carries the job ID, set in RunMaster
+var logger = slog.Default()
+
+var (
+	valuesRead = metrics.NewCounter("mapreduce_master_values_read_total",
+		"Values read from the input file.")
+	chunkValuesSent = metrics.NewCounterVec("mapreduce_master_chunk_values_total",
+		"Values sent to each mapper.", "mapper")
+)
+
+// Options are the job settings given on the command line
+type Options struct {
+	// Secret enables authentication when not nil
+	Secret []byte
+	// OutputMode is "parts" (one file per reducer, the default) or "merged" (a single file)
+	OutputMode string
+	// InputFormat and OutputFormat are ioformat names, the input format may be "auto"
+	InputFormat  string
+	OutputFormat string
+	// Output is "-" to write the merged output to stdout instead of output_dir
+	Output string
+	// SharedInput has mappers read their split of the input from the same path, instead of
+	// the master reading it and sending each mapper a chunk
+	SharedInput bool
+	// KeyField is the 1-based field of text lines to sort them by, the whole lines are written
+	// back in key order. 0 sorts the lines as values.
+	KeyField int
+	// Delimiter separates the fields of a line, "," when empty
+	Delimiter string
+	// KeyType is the keys.Parse name of the type of the keys, int64 when empty
+	KeyType string
+	// SortKeys lists the columns of composite keys, in keys.ParseColumns syntax, instead of KeyField and KeyType
+	SortKeys string
+	// Order is asc or desc, asc when empty
+	Order string
+	// Comparator is the name of a comparator registered with keys.Register, ordering string keys
+	Comparator string
+	// Unique keeps one value, or one record of each key, the first of the input
+	Unique bool
+	// Job is sort, the default when empty, histogram or quantiles
+	Job string
+	// Quantiles lists the quantiles between 0 and 1 computed by quantiles jobs, comma-separated
+	Quantiles string
+}
+
 type Config struct {
-	Workers      []string `yaml:"workers"`
-	Mappers      int      `yaml:"mappers"`
-	Reducers     int      `yaml:"-"`
-	TotalWorkers int      `yaml:"-"`
+	Workers     []string    `yaml:"workers"`
+	Mappers     int         `yaml:"mappers"`
+	OutputDir   string      `yaml:"output_dir"`
+	Compression Compression `yaml:"compression"`
+	// Parallelism bounds the calls made to workers at once, 0 means one per worker
+	Parallelism int `yaml:"parallelism"`
+	// AssignTimeout is the deadline of each AssignRole call
+	AssignTimeout time.Duration `yaml:"assign_timeout"`
+	// ChunkTimeout is the deadline of each SendChunk call, which returns once the mapper shuffled its chunk
+	ChunkTimeout time.Duration `yaml:"chunk_timeout"`
+	Reducers     int           `yaml:"-"`
+	TotalWorkers int           `yaml:"-"`
+}
+
+// Compression selects where gzip is used, each setting is independent
+type Compression struct {
+	// Input is "auto" (by extension or content), "gzip" or "none"
+	Input string `yaml:"input"`
+	// Shuffle compresses the chunks sent to mappers and the values sent to reducers
+	Shuffle bool `yaml:"shuffle"`
+	// Output compresses the part files, or the merged file
+	Output bool `yaml:"output"`
+	// BatchEncoding is how mappers encode the sorted values sent to reducers:
+	// "raw", "delta" (zigzag varint deltas) or "delta-flate" (deltas compressed with DEFLATE)
+	BatchEncoding string `yaml:"batch_encoding"`
+}
+
+// batchEncodings maps the names of Compression.BatchEncoding to their protocol value
+var batchEncodings = map[string]pb.BatchEncoding{
+	"raw":         pb.BatchEncoding_BATCH_RAW,
+	"delta":       pb.BatchEncoding_BATCH_DELTA_VARINT,
+	"delta-flate": pb.BatchEncoding_BATCH_DELTA_VARINT_FLATE,
+}
+
+// jobKinds maps the names of Options.Job to their protocol value
+var jobKinds = map[string]pb.JobKind{
+	"":          pb.JobKind_JOB_SORT,
+	"sort":      pb.JobKind_JOB_SORT,
+	"histogram": pb.JobKind_JOB_HISTOGRAM,
+	"quantiles": pb.JobKind_JOB_QUANTILES,
+}
+
+// mapperTokenTTL bounds the mapper tokens to the map phase: mappers only call reducers while the master
+// waits for their chunks or splits, which it does after assigning roles, in waves of cfg.Parallelism calls.
+// Reducers sort and write in the background once notified, so the reduce phase needs no mapper token.
+func mapperTokenTTL(cfg *Config) time.Duration {
+	waves := func(n int) time.Duration {
+		if cfg.Parallelism <= 0 || cfg.Parallelism >= n {
+			return 1
+		}
+		return time.Duration((n + cfg.Parallelism - 1) / cfg.Parallelism)
+	}
+	return waves(cfg.TotalWorkers)*cfg.AssignTimeout + waves(cfg.Mappers)*cfg.ChunkTimeout
 }
 
 // load the configuration file
@@ -28,56 +147,186 @@ func loadConfig(path string) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
-	var cfg Config
+	cfg := Config{
+		OutputDir:     "output",
+		Compression:   Compression{Input: "auto", BatchEncoding: "delta"},
+		AssignTimeout: 10 * time.Second,
+		ChunkTimeout:  10 * time.Minute,
+	}
 	err = yaml.Unmarshal(data, &cfg)
 	if err != nil {
 		return nil, err
 	}
+	if _, ok := batchEncodings[cfg.Compression.BatchEncoding]; !ok {
+		return nil, fmt.Errorf("unknown batch encoding %q, expected raw, delta or delta-flate", cfg.Compression.BatchEncoding)
+	}
 	return &cfg, nil
 }
 
-// read the input file
-func readInput(path string) ([]int64, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
+// expandInputs resolves the --input value, a comma-separated list of files,
+// directories and glob patterns, into the list of input files. "-" stands for stdin. Files in directories
+// and glob matches are taken in name order, hidden files are skipped. A file listed several times is read once.
+func expandInputs(spec string) ([]string, error) {
+	var paths []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		if clean := filepath.Clean(path); !seen[clean] {
+			seen[clean] = true
+			paths = append(paths, path)
+		}
 	}
-	lines := splitLines(string(data))
-	var nums []int64
-	for _, line := range lines {
-		if line == "" {
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
 			continue
 		}
-		var n int64
-		_, err := fmt.Sscan(line, &n)
+		if entry == "-" {
+			add(entry)
+			continue
+		}
+		var matches []string
+		if strings.ContainsAny(entry, "*?[") {
+			var err error
+			if matches, err = filepath.Glob(entry); err != nil {
+				return nil, fmt.Errorf("pattern %q: %w", entry, err)
+			}
+			// like the shell, only a pattern starting with a dot matches hidden files
+			if !strings.HasPrefix(filepath.Base(entry), ".") {
+				matches = slices.DeleteFunc(matches, func(m string) bool {
+					return strings.HasPrefix(filepath.Base(m), ".")
+				})
+			}
+		} else {
+			matches = []string{entry}
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no input file matches %q", entry)
+		}
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, err
+			}
+			if !info.IsDir() {
+				add(match)
+				continue
+			}
+			entries, err := os.ReadDir(match)
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range entries {
+				if e.Type().IsRegular() && !strings.HasPrefix(e.Name(), ".") {
+					add(filepath.Join(match, e.Name()))
+				}
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no input file in %q", spec)
+	}
+	return paths, nil
+}
+
+// readInputs reads the keys of the values of type t of every input file in order
+func readInputs(paths []string, format ioformat.Format, t ioformat.Type, compression string) ([]int64, error) {
+	var values []int64
+	for _, path := range paths {
+		v, err := readInput(path, format, t, compression)
 		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		values = append(values, v...)
+	}
+	return values, nil
+}
+
+// read the input file, decompressing it if needed
+func readInput(path string, format ioformat.Format, t ioformat.Type, compression string) ([]int64, error) {
+	var f *os.File
+	if path == "-" {
+		f = os.Stdin
+	} else {
+		var err error
+		if f, err = os.Open(path); err != nil {
 			return nil, err
 		}
-		nums = append(nums, n)
+		defer f.Close()
 	}
-	return nums, nil
+	r, closer, err := ioformat.Decompress(f, path, compression)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	return ioformat.ReadAll(r, format, t)
 }
 
-// split the lines of input file
-func splitLines(s string) []string {
-	var lines []string
-	start := 0
-	for i, c := range s {
-		if c == '\n' || c == '\r' {
-			if i > start {
-				lines = append(lines, s[start:i])
-			}
-			start = i + 1
+// readRecordInputs reads every input file in order as text records
+func readRecordInputs(paths []string, compression string, parser keys.Parser) ([]*pb.Record, error) {
+	var records []*pb.Record
+	for _, path := range paths {
+		var err error
+		records, err = readRecordInput(path, compression, parser, records)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
 		}
 	}
-	if start < len(s) {
-		lines = append(lines, s[start:])
+	return records, nil
+}
+
+// readRecordInput appends the records of an input file to records, decompressing it if needed
+func readRecordInput(path, compression string, parser keys.Parser, records []*pb.Record) ([]*pb.Record, error) {
+	var f *os.File
+	if path == "-" {
+		f = os.Stdin
+	} else {
+		var err error
+		if f, err = os.Open(path); err != nil {
+			return nil, err
+		}
+		defer f.Close()
+	}
+	r, closer, err := ioformat.Decompress(f, path, compression)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	rr := keys.NewReader(r, parser)
+	rr.Seq = int64(len(records))
+	for {
+		rec, err := rr.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+}
+
+// callOptions returns the options of calls carrying values, compressed if enabled
+func callOptions(compress bool) []grpc.CallOption {
+	if compress {
+		return []grpc.CallOption{grpc.UseCompressor(gzip.Name)}
 	}
-	return lines
+	return nil
 }
 
 func dialWorker(address string) (pb.WorkerServiceClient, *grpc.ClientConn, error) {
-	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(
+			metrics.UnaryClientInterceptor(),
+			tracing.UnaryClientInterceptor(),
+			auth.UnaryClientInterceptor(func() string { return masterToken }),
+		),
+		grpc.WithChainStreamInterceptor(
+			metrics.StreamClientInterceptor(),
+			tracing.StreamClientInterceptor(),
+			auth.StreamClientInterceptor(func() string { return masterToken }),
+		),
+	)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -85,172 +334,588 @@ func dialWorker(address string) (pb.WorkerServiceClient, *grpc.ClientConn, error
 	return client, conn, nil
 }
 
-func assignRole(client pb.WorkerServiceClient, isMapper bool, reducers []*pb.ReducerInfo, totalMappers int32, intervalStart, intervalEnd int64) error {
-	_, err := client.AssignRole(context.Background(), &pb.AssignRoleRequest{
-		IsMapper:      isMapper,
-		Reducers:      reducers,
-		TotalMappers:  totalMappers,
-		IntervalStart: intervalStart,
-		IntervalEnd:   intervalEnd,
-	})
+func assignRole(ctx context.Context, client pb.WorkerServiceClient, req *pb.AssignRoleRequest) error {
+	req.JobId = jobID
+	_, err := client.AssignRole(ctx, req)
 	return err
 }
 
-func sendChunk(client pb.WorkerServiceClient, values []int64) error {
-	_, err := client.SendChunk(context.Background(), &pb.SendChunkRequest{
-		Values: values,
-	})
+func sendChunk(ctx context.Context, client pb.WorkerServiceClient, req *pb.SendChunkRequest, compress bool) error {
+	_, err := client.SendChunk(ctx, req, callOptions(compress)...)
 	return err
 }
 
-func assignMapper(addr string, reducerInfos []*pb.ReducerInfo) {
+func assignMapper(ctx context.Context, addr string, reducerInfos []*pb.ReducerInfo, compression Compression, order keys.Order, unique bool, job pb.JobKind, ttl time.Duration) error {
+	ctx, span := tracing.StartTrack(ctx, "assign mapper", "worker", addr)
+	defer span.End()
 	client, conn, err := dialWorker(addr)
 	if err != nil {
-		log.Fatalf("Failed to connect to mapper %s: %v", addr, err)
+		return fmt.Errorf("connect to mapper %s: %w", addr, err)
 	}
 	defer func() {
 		if err := conn.Close(); err != nil {
-			log.Printf("Failed to close connection: %v", err)
+			logger.Warn("Failed to close connection", "worker", addr, "error", err)
 		}
 	}()
-	err = assignRole(client, true, reducerInfos, 0, 0, 0)
+	// the mapper uses this token to prove to reducers that it was assigned by the master to this job
+	var token string
+	if authority != nil {
+		token, err = authority.Issue(auth.RoleMapper, addr, jobID, ttl)
+		if err != nil {
+			return fmt.Errorf("issue token for mapper %s: %w", addr, err)
+		}
+	}
+	err = assignRole(ctx, client, &pb.AssignRoleRequest{
+		IsMapper:        true,
+		Reducers:        reducerInfos,
+		MapperToken:     token,
+		CompressShuffle: compression.Shuffle,
+		BatchEncoding:   batchEncodings[compression.BatchEncoding],
+		KeyType:         order.Type,
+		Comparator:      order.Comparator,
+		Descending:      order.Descending,
+		Unique:          unique,
+		Job:             job,
+	})
 	if err != nil {
-		log.Fatalf("Failed to assign mapper role: %v", err)
+		return fmt.Errorf("assign mapper role to %s: %w", addr, err)
 	}
-	fmt.Printf("%s Assigned mapper role to %s\n", time.Now().Format("2006/01/02 15:04:05"), addr)
+	logger.Info("Assigned mapper role", "phase", "assign", "worker", addr, "role", "mapper")
+	return nil
 }
 
-func assignReducer(addr string, cfg *Config, interval [2]int64) {
+func assignReducer(ctx context.Context, cfg *Config, partition int, info *pb.ReducerInfo, outputMode pb.OutputMode, outputFormat ioformat.Format, parser keys.Parser, order keys.Order, unique bool, job pb.JobKind) error {
+	addr := info.Address
+	ctx, span := tracing.StartTrack(ctx, "assign reducer", "worker", addr)
+	defer span.End()
 	client, conn, err := dialWorker(addr)
 	if err != nil {
-		log.Fatalf("Failed to connect to reducer %s: %v", addr, err)
+		return fmt.Errorf("connect to reducer %s: %w", addr, err)
 	}
 	defer func() {
 		if err := conn.Close(); err != nil {
-			log.Printf("Failed to close connection: %v", err)
+			logger.Warn("Failed to close connection", "worker", addr, "error", err)
 		}
 	}()
-	err = assignRole(client, false, nil, int32(cfg.Mappers), interval[0], interval[1])
+	err = assignRole(ctx, client, &pb.AssignRoleRequest{
+		IsMapper:       false,
+		TotalMappers:   int32(cfg.Mappers),
+		IntervalStart:  info.IntervalStart,
+		IntervalEnd:    info.IntervalEnd,
+		Partition:      int32(partition),
+		OutputDir:      cfg.OutputDir,
+		OutputMode:     outputMode,
+		OutputFormat:   pb.DataFormat(outputFormat),
+		CompressOutput: cfg.Compression.Output,
+		Records:        parser.Records(),
+		KeyType:        parser.Type,
+		Comparator:     order.Comparator,
+		Descending:     order.Descending,
+		Unique:         unique,
+		Job:            job,
+	})
 	if err != nil {
-		log.Fatalf("Failed to assign reducer role: %v", err)
+		return fmt.Errorf("assign reducer role to %s: %w", addr, err)
 	}
-	fmt.Printf("%s Assigned reducer role to %s (interval [%d, %d))\n", time.Now().Format("2006/01/02 15:04:05"), addr, interval[0], interval[1])
+	lowest, highest := order.Bounds(partition, cfg.Reducers)
+	start, end := formatInterval(info, parser.Type, lowest, highest)
+	logger.Info("Assigned reducer role", "phase", "assign", "worker", addr, "role", "reducer",
+		"interval_start", start, "interval_end", end)
+	return nil
 }
 
-func RunMaster(configPath, inputPath string) {
-	cfg, err := loadConfig(configPath)
+// distributeChunk sends a chunk of values or records to a mapper, the call returns once the mapper shuffled it
+func distributeChunk(ctx context.Context, addr string, chunk *pb.SendChunkRequest, compress bool) error {
+	n := len(chunk.Values) + len(chunk.Records)
+	ctx, span := tracing.StartTrack(ctx, "send chunk", "worker", addr, "values", n)
+	defer span.End()
+	client, conn, err := dialWorker(addr)
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		return fmt.Errorf("connect to mapper %s: %w", addr, err)
 	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logger.Warn("Failed to close connection", "worker", addr, "error", err)
+		}
+	}()
+	dash.setChunk(addr, int64(n))
+	if err := sendChunk(ctx, client, chunk, compress); err != nil {
+		return fmt.Errorf("send chunk to mapper %s: %w", addr, err)
+	}
+	chunkValuesSent.With(addr).Add(float64(n))
+	logger.Info("Sent chunk to mapper", "phase", "map", "worker", addr, "values", n)
+	logger.Debug("Chunk content", "phase", "map", "worker", addr, "values", logging.Preview(chunk.Values))
+	return nil
+}
 
-	cfg.TotalWorkers = len(cfg.Workers)
-	cfg.Reducers = cfg.TotalWorkers - cfg.Mappers
+// partitionSamples cuts samples sorted in ascending order into one key interval for each reducer, each
+// holding about the same number of samples. The lowest interval has no lower bound and the highest no
+// upper bound. Reducers get the intervals in ascending order, or in descending order for descending jobs.
+func partitionSamples(samples []*pb.Record, reducerAddrs []string, order keys.Order) []*pb.ReducerInfo {
+	intervalLength := len(samples) / len(reducerAddrs)
+	infos := make([]*pb.ReducerInfo, len(reducerAddrs))
+	addrs := reducerAddrs
+	if order.Descending {
+		addrs = slices.Clone(reducerAddrs)
+		slices.Reverse(addrs)
+	}
+	for i, addr := range addrs {
+		ri := &pb.ReducerInfo{
+			Address:       addr,
+			IntervalStart: math.MinInt64,
+			IntervalEnd:   math.MaxInt64,
+		}
+		if i != 0 {
+			start := samples[i*intervalLength]
+			ri.IntervalStart, ri.KeyStart = start.Key, start.KeyBytes
+		}
+		if i != len(reducerAddrs)-1 {
+			end := samples[(i+1)*intervalLength]
+			ri.IntervalEnd, ri.KeyEnd = end.Key, end.KeyBytes
+		}
+		if keys.Bytes(order.Type) {
+			ri.IntervalStart, ri.IntervalEnd = 0, 0
+		}
+		infos[i] = ri
+	}
+	if order.Descending {
+		slices.Reverse(infos)
+	}
+	return infos
+}
 
-	fmt.Printf("%s Starting master with %d total nodes: %d mappers and %d reducers\n", time.Now().Format("2006/01/02 15:04:05"), cfg.TotalWorkers, cfg.Mappers, cfg.Reducers)
+// formatInterval returns the bounds of the interval of a reducer as text for logs and reports.
+// Int64 intervals span math.MinInt64 to math.MaxInt64, the missing bounds of other key types are empty.
+func formatInterval(ri *pb.ReducerInfo, keyType pb.KeyType, lowest, highest bool) (string, string) {
+	if keyType == pb.KeyType_KEY_INT64 {
+		return strconv.FormatInt(ri.IntervalStart, 10), strconv.FormatInt(ri.IntervalEnd, 10)
+	}
+	var start, end string
+	if !lowest {
+		start = keys.Format(keyType, &pb.Record{Key: ri.IntervalStart, KeyBytes: ri.KeyStart})
+	}
+	if !highest {
+		end = keys.Format(keyType, &pb.Record{Key: ri.IntervalEnd, KeyBytes: ri.KeyEnd})
+	}
+	return start, end
+}
 
-	allValues, err := readInput(inputPath)
+// splitChunks splits values into m contiguous chunks, the first ones holding
+// one more value than the last ones if there is a remainder
+func splitChunks[T any](values []T, m int) [][]T {
+	baseChunkSize := len(values) / m
+	remainder := len(values) % m
+	chunks := make([][]T, m)
+	start := 0
+	for i := range chunks {
+		end := start + baseChunkSize
+		if i < remainder {
+			end++
+		}
+		chunks[i] = values[start:end]
+		start = end
+	}
+	return chunks
+}
+
+// fatal logs an error and exits, the job cannot continue without every worker
+func fatal(msg string, args ...any) {
+	logger.Error(msg, args...)
+	os.Exit(1)
+}
+
+// newJobID returns a random identifier used to correlate the logs of a job
+func newJobID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+func RunMaster(configPath, inputPath string, opts Options) {
+	jobID = newJobID()
+	logger = slog.With("job", jobID)
+	ctx, jobSpan := tracing.StartTrace(context.Background(), jobID, "job")
+	defer func() {
+		jobSpan.End()
+		if err := tracing.Flush(jobID); err != nil {
+			logger.Warn("Failed to write trace", "error", err)
+		}
+	}()
+
+	cfg, err := loadConfig(configPath)
 	if err != nil {
-		log.Fatalf("Failed to read input: %v", err)
+		fatal("Failed to load config", "path", configPath, "error", err)
+	}
+
+	if opts.Output == "-" {
+		// the globally sorted stream goes to stdout, which only the master can write in order
+		if opts.OutputMode == "parts" {
+			fatal("Parts cannot be written to stdout, --output=- requires merged output mode", "output_mode", opts.OutputMode)
+		}
+		opts.OutputMode = "merged"
+		progressOut = os.Stderr
+	} else if opts.Output != "" {
+		fatal("Unknown output, expected - for stdout or nothing for output_dir", "output", opts.Output)
 	}
 
-	if len(allValues) == 0 {
-		log.Fatalf("No input data provided.")
+	var outputMode pb.OutputMode
+	switch opts.OutputMode {
+	case "", "parts":
+		outputMode = pb.OutputMode_OUTPUT_PARTS
+	case "merged":
+		outputMode = pb.OutputMode_OUTPUT_MERGED
+	default:
+		fatal("Unknown output mode, expected parts or merged", "output_mode", opts.OutputMode)
 	}
 
-	// Sample 1% of the input values
-	sampleSize := len(allValues) / 100
-	if sampleSize == 0 {
-		sampleSize = 1
+	inputFormat, err := ioformat.Parse(opts.InputFormat)
+	if err != nil {
+		fatal("Invalid input format", "error", err)
 	}
-	sampledValues := make([]int64, sampleSize)
-	for i := range sampledValues {
-		sampledValues[i] = allValues[rand.Intn(len(allValues))]
+	outputFormat, err := ioformat.Parse(opts.OutputFormat)
+	if err != nil || outputFormat == ioformat.Auto {
+		fatal("Invalid output format", "output_format", opts.OutputFormat)
+	}
+	// records are text lines written back whole, only their key is sorted
+	parser := keys.Parser{Field: ioformat.KeyField{Field: opts.KeyField, Delimiter: opts.Delimiter}}
+	if opts.KeyType != "" {
+		if parser.Type, err = keys.Parse(opts.KeyType); err != nil {
+			fatal("Invalid key type", "error", err)
+		}
+	}
+	if opts.SortKeys != "" {
+		if opts.KeyField != 0 {
+			fatal("Sort keys replace the key field, set only one of them", "key_field", opts.KeyField, "sort_keys", opts.SortKeys)
+		}
+		if parser.Columns, err = keys.ParseColumns(opts.SortKeys); err != nil {
+			fatal("Invalid sort keys", "error", err)
+		}
+		parser.Type = pb.KeyType_KEY_COMPOSITE
+	}
+	order := keys.Order{Type: parser.Type, Comparator: opts.Comparator}
+	switch opts.Order {
+	case "", "asc":
+	case "desc":
+		order.Descending = true
+	default:
+		fatal("Unknown order, expected asc or desc", "order", opts.Order)
+	}
+	if err := order.Validate(); err != nil {
+		fatal("Invalid comparator", "error", err)
+	}
+	job, ok := jobKinds[opts.Job]
+	if !ok {
+		fatal("Unknown job, expected sort, histogram or quantiles", "kind", opts.Job)
+	}
+	var qs []float64
+	if job == pb.JobKind_JOB_QUANTILES {
+		// reducers keep their sorted values, the master only reads the values at the requested ranks
+		if parser.Records() || order.Descending {
+			fatal("Quantiles rank values in ascending order, they do not apply to records or descending jobs")
+		}
+		if qs, err = parseQuantiles(opts.Quantiles); err != nil {
+			fatal("Invalid quantiles", "error", err)
+		}
+		progressOut = os.Stderr
+	}
+	if job == pb.JobKind_JOB_HISTOGRAM {
+		// a histogram counts values, its lines hold a value and a count
+		if parser.Records() || opts.Unique {
+			fatal("A histogram counts values, it does not apply to records or unique jobs")
+		}
+		if outputFormat != ioformat.Text {
+			fatal("A histogram is written as text", "output_format", opts.OutputFormat)
+		}
+	}
+	records := parser.Records()
+	if records {
+		if inputFormat == ioformat.Auto {
+			inputFormat = ioformat.Text
+		}
+		if inputFormat != ioformat.Text || outputFormat != ioformat.Text {
+			fatal("Records are text lines, the input and output formats must be text",
+				"input_format", opts.InputFormat, "output_format", opts.OutputFormat)
+		}
 	}
 
-	// Sort the sampled values
-	sort.Slice(sampledValues, func(i, j int) bool {
-		return sampledValues[i] < sampledValues[j]
-	})
+	if opts.Secret != nil {
+		authority = auth.NewAuthority(opts.Secret)
+		masterToken, err = authority.Issue(auth.RoleMaster, "master", "", tokenTTL)
+		if err != nil {
+			fatal("Failed to issue master token", "error", err)
+		}
+	}
+
+	cfg.TotalWorkers = len(cfg.Workers)
+	cfg.Reducers = cfg.TotalWorkers - cfg.Mappers
+	dash.start(jobID, cfg)
+
+	logger.Info("Starting master", "workers", cfg.TotalWorkers, "mappers", cfg.Mappers, "reducers", cfg.Reducers)
+
+	// a previous run's marker must not vouch for this run's output, nor its files be mixed with it,
+	// but only a run writing to output_dir replaces them, quantiles are printed
+	if opts.Output != "-" && job != pb.JobKind_JOB_QUANTILES {
+		if err := clearOutput(cfg.OutputDir); err != nil {
+			fatal("Failed to clear output", "dir", cfg.OutputDir, "error", err)
+		}
+	}
+
+	// total is exact once every value was read, in shared input mode it is estimated until then
+	var total atomic.Int64
+	var allValues []int64
+	var allRecords []*pb.Record
+	var samples []*pb.Record
+	var shared *sharedInput
+	var span *tracing.Span
+	inputPaths, err := expandInputs(inputPath)
+	if err != nil {
+		fatal("Invalid input", "phase", "read", "input", inputPath, "error", err)
+	}
+	if opts.SharedInput {
+		// mappers read their own splits, the master only samples the files
+		dash.setPhase("sample")
+		_, span = tracing.Start(ctx, "sample", "input", inputPath, "files", len(inputPaths))
+		shared, err = openShared(inputPaths, inputFormat, parser)
+		if err != nil {
+			fatal("Failed to open shared input", "phase", "read", "path", inputPath, "error", err)
+		}
+		defer shared.Close()
+		var estimate int64
+		samples, estimate, err = shared.sample()
+		span.End()
+		if err != nil {
+			fatal("Failed to sample input", "phase", "sample", "path", inputPath, "error", err)
+		}
+		if len(samples) == 0 {
+			fatal("No input data provided", "phase", "read", "path", inputPath)
+		}
+		total.Store(estimate)
+		logger.Info("Sampled shared input", "phase", "sample", "files", len(shared.files),
+			"bytes", shared.size, "estimated_values", estimate, "samples", len(samples))
+	} else {
+		dash.setPhase("read")
+		_, span = tracing.Start(ctx, "read input", "input", inputPath, "files", len(inputPaths))
+		n := 0
+		if records {
+			allRecords, err = readRecordInputs(inputPaths, cfg.Compression.Input, parser)
+			n = len(allRecords)
+		} else {
+			allValues, err = readInputs(inputPaths, inputFormat, keys.Numeric(parser.Type), cfg.Compression.Input)
+			n = len(allValues)
+		}
+		span.End()
+		if err != nil {
+			fatal("Failed to read input", "phase", "read", "path", inputPath, "error", err)
+		}
 
-	intervalLength := sampleSize / cfg.Reducers
-	// Calculate intervals for each reducer
-	intervals := make([][2]int64, cfg.Reducers)
-	for i := 0; i < cfg.Reducers; i++ {
-		start := sampledValues[i*intervalLength]
-		if i == 0 {
-			start = math.MinInt64
+		if n == 0 {
+			fatal("No input data provided", "phase", "read", "path", inputPath)
 		}
-		end := int64(math.MaxInt64)
-		if i != cfg.Reducers-1 {
-			end = sampledValues[int64((i+1)*intervalLength)]
+		valuesRead.Add(float64(n))
+		total.Store(int64(n))
+
+		// Sample 1% of the input values, or of the keys of the records
+		dash.setPhase("sample")
+		_, span = tracing.Start(ctx, "sample")
+		samples = make([]*pb.Record, sampleCount(int64(n)))
+		for i := range samples {
+			j := mathrand.Intn(n)
+			if records {
+				samples[i] = allRecords[j]
+			} else {
+				samples[i] = &pb.Record{Key: allValues[j]}
+			}
 		}
-		intervals[i] = [2]int64{start, end}
+
+		logger.Info("Sampled input", "phase", "sample", "values", n, "samples", len(samples))
+		span.End()
 	}
+	dash.setTotal(total.Load())
 
 	// Slice of addresses of mappers and reducers from workers addresses list
 	mapperAddrs := cfg.Workers[:cfg.Mappers]
 	reducerAddrs := cfg.Workers[cfg.Mappers:]
 
-	// Create reducer info protobuf variable for each reducer
-	var reducerInfos []*pb.ReducerInfo
-	for i, addr := range reducerAddrs {
-		ri := &pb.ReducerInfo{
-			Address:       addr,
-			IntervalStart: intervals[i][0],
-			IntervalEnd:   intervals[i][1],
+	// Sort the samples, then cut them into one interval for each reducer
+	_, span = tracing.Start(ctx, "partition", "reducers", cfg.Reducers)
+	ascending := order
+	ascending.Descending = false
+	slices.SortFunc(samples, ascending.Compare())
+	reducerInfos := partitionSamples(samples, reducerAddrs, order)
+	span.End()
+
+	// Assign roles to all workers concurrently, chunks are only sent once every reducer is ready
+	dash.setPhase("assign")
+	err = fanOut(ctx, cfg.TotalWorkers, cfg.Parallelism, cfg.AssignTimeout, func(ctx context.Context, i int) error {
+		if i < cfg.Mappers {
+			return assignMapper(ctx, mapperAddrs[i], reducerInfos, cfg.Compression, order, opts.Unique, job, mapperTokenTTL(cfg))
+		}
+		r := i - cfg.Mappers
+		if err := assignReducer(ctx, cfg, r, reducerInfos[r], outputMode, outputFormat, parser, order, opts.Unique, job); err != nil {
+			return err
 		}
-		reducerInfos = append(reducerInfos, ri)
+		lowest, highest := order.Bounds(r, cfg.Reducers)
+		start, end := formatInterval(reducerInfos[r], parser.Type, lowest, highest)
+		dash.setInterval(reducerAddrs[r], start, end)
+		return nil
+	})
+	if err != nil {
+		fatal("Failed to assign roles", "phase", "assign", "error", err)
 	}
 
-	// Assign roles to workers
-	// Mappers:
-	for _, addr := range mapperAddrs {
-		assignMapper(addr, reducerInfos)
+	// Report progress until every reducer wrote its output. A failed worker ends the job right away,
+	// even while chunks are still being sent.
+	type progressResult struct {
+		mappers, reducers []*pb.GetStatusResponse
 	}
+	progressDone := make(chan progressResult, 1)
+	go func() {
+		_, span := tracing.Start(ctx, "wait for reducers")
+		defer span.End()
+		mappers, reducers, err := trackProgress(ctx, cfg.Workers[:cfg.Mappers], cfg.Workers[cfg.Mappers:], &total)
+		if err != nil {
+			fatal("Failed to track job progress", "error", err)
+		}
+		progressDone <- progressResult{mappers, reducers}
+	}()
 
-	// Reducers:
-	for i, addr := range reducerAddrs {
-		assignReducer(addr, cfg, intervals[i])
+	dash.setPhase("map")
+	if shared != nil {
+		// Split the files into byte ranges, one for each mapper, which read them concurrently
+		splits, err := shared.splits(cfg.Mappers)
+		if err != nil {
+			fatal("Failed to split input", "phase", "map", "error", err)
+		}
+		read := make([]int64, cfg.Mappers)
+		err = fanOut(ctx, cfg.Mappers, cfg.Parallelism, cfg.ChunkTimeout, func(ctx context.Context, i int) error {
+			n, err := assignSplits(ctx, mapperAddrs[i], splits[i], parser)
+			read[i] = n
+			return err
+		})
+		if err != nil {
+			fatal("Failed to assign splits", "phase", "map", "error", err)
+		}
+		var n int64
+		for _, r := range read {
+			n += r
+		}
+		valuesRead.Add(float64(n))
+		total.Store(n)
+		dash.setTotal(n)
+	} else {
+		// Split input into chunks, one for each mapper, and send them concurrently so that mappers work in parallel
+		chunks := make([]*pb.SendChunkRequest, cfg.Mappers)
+		if records {
+			for i, c := range splitChunks(allRecords, cfg.Mappers) {
+				chunks[i] = &pb.SendChunkRequest{Records: c}
+			}
+		} else {
+			for i, c := range splitChunks(allValues, cfg.Mappers) {
+				chunks[i] = &pb.SendChunkRequest{Values: c}
+			}
+		}
+		err = fanOut(ctx, cfg.Mappers, cfg.Parallelism, cfg.ChunkTimeout, func(ctx context.Context, i int) error {
+			return distributeChunk(ctx, mapperAddrs[i], chunks[i], cfg.Compression.Shuffle)
+		})
+		if err != nil {
+			fatal("Failed to distribute chunks", "phase", "map", "error", err)
+		}
 	}
 
-	// Split input into chunks, one for each mapper
-	m := cfg.Mappers
-	// calculate base chunk size and remainder
-	// first chunks will have 1 more value than the last chunks if there is a remainder
-	baseChunkSize := len(allValues) / m
-	remainder := len(allValues) % m
+	logger.Info("Master finished distributing tasks, waiting for reducers")
+	dash.setPhase("reduce")
 
-	for i, addr := range mapperAddrs {
-		start := i * baseChunkSize
-		if i < remainder {
-			start += i
-		} else {
-			start += remainder
+	// Mappers notify reducers directly, the master only watches their status
+	result := <-progressDone
+	var duplicates int64
+	if opts.Unique {
+		for _, s := range append(slices.Clone(result.mappers), result.reducers...) {
+			if s != nil {
+				duplicates += s.Duplicates
+			}
 		}
-		end := start + baseChunkSize
-		if i < remainder {
-			end++
+		logger.Info("Dropped duplicates", "phase", "reduce", "input_values", total.Load(),
+			"duplicates", duplicates, "distinct", total.Load()-duplicates)
+	}
+
+	if job == pb.JobKind_JOB_QUANTILES {
+		dash.setPhase("query")
+		counts := make([]int64, len(result.reducers))
+		var n int64
+		for r, s := range result.reducers {
+			if s == nil || s.Part == nil {
+				fatal("Reducer did not report its values", "phase", "write", "worker", reducerAddrs[r])
+			}
+			counts[r] = s.Part.Records
+			n += counts[r]
 		}
-		if end > len(allValues) {
-			end = len(allValues)
+		if n != total.Load()-duplicates {
+			fatal("Reducers hold an incomplete input", "phase", "write", "values", n, "input_values", total.Load(),
+				"duplicates", duplicates)
 		}
-		chunk := allValues[start:end]
-		client, conn, err := dialWorker(addr)
+		quantiles := locateQuantiles(qs, counts)
+		queryCtx, span := tracing.Start(ctx, "query quantiles", "quantiles", len(quantiles))
+		err := queryQuantiles(queryCtx, reducerAddrs, quantiles)
+		span.End()
 		if err != nil {
-			log.Fatalf("Failed to send chunk to mapper %s: %v", addr, err)
+			fatal("Failed to query quantiles", "phase", "write", "error", err)
 		}
-		err = sendChunk(client, chunk)
+		t := keys.Numeric(parser.Type)
+		for _, q := range quantiles {
+			logger.Info("Quantile", "phase", "write", "quantile", q.q, "rank", q.rank,
+				"worker", reducerAddrs[q.reducer], "value", t.FormatKey(q.value))
+		}
+		fmt.Print(formatQuantiles(quantiles, t))
+		dash.setPhase("done")
+		logger.Info("Job finished, shutting down")
+		return
+	}
+
+	if opts.Output == "-" {
+		dash.setPhase("merge")
+		mergeCtx, span := tracing.Start(ctx, "stream outputs")
+		out := bufio.NewWriterSize(os.Stdout, 1<<20)
+		merged, err := streamOutputs(mergeCtx, out, reducerAddrs, outputFormat, cfg.Compression.Output, parser, job)
+		if err == nil {
+			err = out.Flush()
+		}
+		span.End()
 		if err != nil {
-			log.Fatalf("Failed to send chunk to mapper: %v", err)
+			fatal("Failed to write output to stdout", "phase", "write", "error", err)
 		}
-		fmt.Printf("%s Sent chunk with %d values to mapper %s\n", time.Now().Format("2006/01/02 15:04:05"), len(chunk), addr)
-		if err := conn.Close(); err != nil {
-			log.Printf("Failed to close connection: %v", err)
+		if job == pb.JobKind_JOB_HISTOGRAM && merged.Values != total.Load() {
+			fatal("Output is incomplete", "phase", "write", "counted_values", merged.Values, "input_values", total.Load())
+		}
+		if job != pb.JobKind_JOB_HISTOGRAM && merged.Records != total.Load()-duplicates {
+			fatal("Output is incomplete", "phase", "write", "records", merged.Records, "input_values", total.Load(),
+				"duplicates", duplicates)
 		}
+		logger.Info("Output complete", "phase", "write", "output", "stdout", "records", merged.Records, "bytes", merged.Bytes)
+		dash.setPhase("done")
+		logger.Info("Job finished, shutting down")
+		return
 	}
 
-	// The master does not wait for final outputs.
-	// Mappers will notify reducers directly and reducers will write their final outputs.
-	// Master is done here.
-	fmt.Printf("%s Master finished distributing tasks, shutting down...\n", time.Now().Format("2006/01/02 15:04:05"))
+	var merged *ManifestFile
+	if outputMode == pb.OutputMode_OUTPUT_MERGED {
+		dash.setPhase("merge")
+		mergeCtx, span := tracing.Start(ctx, "merge outputs")
+		merged, err = mergeOutputs(mergeCtx, cfg.OutputDir, reducerAddrs, outputFormat, cfg.Compression.Output, parser, job)
+		span.End()
+		if err != nil {
+			fatal("Failed to merge reducer outputs", "phase", "write", "dir", cfg.OutputDir, "error", err)
+		}
+	}
+
+	_, span = tracing.Start(ctx, "write manifest")
+	err = writeManifest(cfg, reducerInfos, order, result.reducers, total.Load(), opts.Unique, duplicates, job, merged)
+	span.End()
+	if err != nil {
+		fatal("Failed to complete output", "phase", "write", "dir", cfg.OutputDir, "error", err)
+	}
+	logger.Info("Output complete", "phase", "write", "dir", cfg.OutputDir, "parts", len(reducerAddrs))
+	dash.setPhase("done")
+	logger.Info("Job finished, shutting down")
 }
@@ -0,0 +1,129 @@
+package master
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"mapreduce/keys"
+	pb "mapreduce/proto"
+)
+
+func TestExpandInputs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a", "b", "c.txt", ".hidden", "sub/d"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("1\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	in := func(names ...string) []string {
+		paths := make([]string, len(names))
+		for i, name := range names {
+			paths[i] = filepath.Join(dir, name)
+		}
+		return paths
+	}
+	tests := []struct {
+		name string
+		spec string
+		want []string
+	}{
+		{"file", in("a")[0], in("a")},
+		{"comma list", in("b")[0] + " , ," + in("a")[0], in("b", "a")},
+		{"directory", dir, in("a", "b", "c.txt")},
+		{"glob", filepath.Join(dir, "*.txt"), in("c.txt")},
+		{"hidden glob", filepath.Join(dir, ".h*"), in(".hidden")},
+		{"glob matching a directory", filepath.Join(dir, "[cs]*"), in("c.txt", "sub/d")},
+		{"duplicates", in("a")[0] + "," + dir + "," + filepath.Join(dir, "*"), in("a", "b", "c.txt", "sub/d")},
+		{"unclean duplicate", in("a")[0] + "," + dir + "/./a", in("a")},
+		{"stdin", "-", []string{"-"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandInputs(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expandInputs(%q) = %q, want %q", tt.spec, got, tt.want)
+			}
+		})
+	}
+	for _, spec := range []string{
+		"",
+		" , ",
+		filepath.Join(dir, "empty"),
+		filepath.Join(dir, "*.gz"),
+		filepath.Join(dir, "missing"),
+		filepath.Join(dir, "[") + "*",
+	} {
+		if got, err := expandInputs(spec); err == nil {
+			t.Errorf("expandInputs(%q) = %q, want an error", spec, got)
+		}
+	}
+}
+
+func TestPartitionSamples(t *testing.T) {
+	var values, strs []*pb.Record
+	for i := 0; i < 12; i++ {
+		values = append(values, &pb.Record{Key: int64(i)})
+		strs = append(strs, &pb.Record{KeyBytes: []byte{'a' + byte(i)}})
+	}
+	addrs := []string{"r0", "r1", "r2"}
+	type interval struct {
+		addr       string
+		start, end int64
+		keyStart   string
+		keyEnd     string
+	}
+	tests := []struct {
+		name    string
+		samples []*pb.Record
+		order   keys.Order
+		want    []interval
+	}{
+		{"asc", values, keys.Order{Type: pb.KeyType_KEY_INT64}, []interval{
+			{"r0", math.MinInt64, 4, "", ""},
+			{"r1", 4, 8, "", ""},
+			{"r2", 8, math.MaxInt64, "", ""},
+		}},
+		{"desc", values, keys.Order{Type: pb.KeyType_KEY_INT64, Descending: true}, []interval{
+			{"r0", 8, math.MaxInt64, "", ""},
+			{"r1", 4, 8, "", ""},
+			{"r2", math.MinInt64, 4, "", ""},
+		}},
+		{"asc strings", strs, keys.Order{Type: pb.KeyType_KEY_STRING}, []interval{
+			{"r0", 0, 0, "", "e"},
+			{"r1", 0, 0, "e", "i"},
+			{"r2", 0, 0, "i", ""},
+		}},
+		{"desc strings", strs, keys.Order{Type: pb.KeyType_KEY_STRING, Descending: true}, []interval{
+			{"r0", 0, 0, "i", ""},
+			{"r1", 0, 0, "e", "i"},
+			{"r2", 0, 0, "", "e"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			infos := partitionSamples(tt.samples, addrs, tt.order)
+			if len(infos) != len(tt.want) {
+				t.Fatalf("partitionSamples returned %d intervals, want %d", len(infos), len(tt.want))
+			}
+			for i, ri := range infos {
+				got := interval{ri.Address, ri.IntervalStart, ri.IntervalEnd, string(ri.KeyStart), string(ri.KeyEnd)}
+				if got != tt.want[i] {
+					t.Errorf("interval %d = %+v, want %+v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
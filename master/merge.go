@@ -0,0 +1,184 @@
+package master
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"mapreduce/ioformat"
+	"mapreduce/keys"
+	pb "mapreduce/proto"
+	"mapreduce/tracing"
+)
+
+// mergedName is the file written in merged output mode
+const mergedName = "merged"
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// mergeOutputs fetches the retained output of every reducer in interval order and
+// concatenates it into a single globally sorted file. Only one batch is held at a time.
+// The file is written under a temporary name, removed if the merge fails.
+func mergeOutputs(ctx context.Context, dir string, reducerAddrs []string, format ioformat.Format, compress bool, parser keys.Parser, job pb.JobKind) (_ *ManifestFile, err error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	name := mergedName
+	if compress {
+		name += ioformat.GzipSuffix
+	}
+	path := filepath.Join(dir, name)
+	tmp := filepath.Join(dir, "."+name+".inprogress")
+	f, err := os.Create(tmp)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(tmp)
+		}
+	}()
+
+	merged, err := streamOutputs(ctx, f, reducerAddrs, format, compress, parser, job)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, err
+	}
+	merged.File = name
+	return merged, nil
+}
+
+// streamOutputs writes the retained output of every reducer to w in interval order,
+// and describes what was written. The File of the result is left empty.
+// In record mode the lines of the records are written instead of values, in histogram jobs a line
+// per value with its count.
+func streamOutputs(ctx context.Context, out io.Writer, reducerAddrs []string, format ioformat.Format, compress bool, parser keys.Parser, job pb.JobKind) (*ManifestFile, error) {
+	sum := crc32.New(crc32c)
+	counter := &byteCounter{}
+	dst := io.MultiWriter(out, sum, counter)
+	var write func(*pb.OutputBatch) error
+	var flush func() error
+	var zw io.Closer
+	var counted int64
+	switch {
+	case job == pb.JobKind_JOB_HISTOGRAM:
+		var z io.Writer
+		z, zw = ioformat.Compress(dst, compress)
+		rw := ioformat.NewRecordWriter(z)
+		t := keys.Numeric(parser.Type)
+		var line []byte
+		write = func(batch *pb.OutputBatch) error {
+			if len(batch.Counts) != len(batch.Values) {
+				return fmt.Errorf("%d counts for %d values", len(batch.Counts), len(batch.Values))
+			}
+			for i, v := range batch.Values {
+				line = t.AppendCount(line[:0], v, batch.Counts[i])
+				if err := rw.Write(line); err != nil {
+					return err
+				}
+				counted += batch.Counts[i]
+			}
+			return nil
+		}
+		flush = rw.Flush
+	case parser.Records():
+		var z io.Writer
+		z, zw = ioformat.Compress(dst, compress)
+		rw := ioformat.NewRecordWriter(z)
+		write = func(batch *pb.OutputBatch) error {
+			for _, r := range batch.Records {
+				if err := rw.Write(keys.Line(parser.Type, r)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		flush = rw.Flush
+	default:
+		w, c, err := ioformat.NewCompressedWriter(dst, format, keys.Numeric(parser.Type), compress)
+		if err != nil {
+			return nil, err
+		}
+		zw = c
+		write = func(batch *pb.OutputBatch) error {
+			for _, v := range batch.Values {
+				if err := w.Write(v); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		flush = w.Flush
+	}
+	var n int64
+	for _, addr := range reducerAddrs {
+		fetched, err := fetchOutput(ctx, addr, write)
+		if err != nil {
+			return nil, fmt.Errorf("fetch output of %s: %w", addr, err)
+		}
+		n += fetched
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return &ManifestFile{
+		Records:  n,
+		Values:   counted,
+		Bytes:    counter.n,
+		Checksum: fmt.Sprintf("crc32c:%08x", sum.Sum32()),
+	}, nil
+}
+
+// fetchOutput streams the retained output of a reducer to write, batch by batch
+func fetchOutput(ctx context.Context, addr string, write func(*pb.OutputBatch) error) (int64, error) {
+	ctx, span := tracing.Start(ctx, "fetch output", "worker", addr)
+	defer span.End()
+	client, conn, err := dialWorker(addr)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	stream, err := client.FetchOutput(ctx, &pb.FetchOutputRequest{JobId: jobID})
+	if err != nil {
+		return 0, err
+	}
+	var records int64
+	for {
+		batch, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return records, err
+		}
+		if err := write(batch); err != nil {
+			return records, err
+		}
+		records += int64(len(batch.Values) + len(batch.Records))
+	}
+	span.SetAttrs("records", records)
+	logger.Info("Fetched reducer output", "phase", "write", "worker", addr, "records", records)
+	return records, nil
+}
+
+type byteCounter struct {
+	n int64
+}
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,309 @@
+package master
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"mapreduce/logging"
+	pb "mapreduce/proto"
+)
+
+const (
+	// pollInterval is how often workers are asked for their status
+	pollInterval = 500 * time.Millisecond
+	// logInterval is how often progress is logged when stdout is not a terminal
+	logInterval = 5 * time.Second
+	// maxMissedPolls is how many polls in a row a worker may not answer before the job fails
+	maxMissedPolls = 20
+	barWidth       = 30
+)
+
+// progressOut receives the progress view, stderr when the job output is written to stdout
+var progressOut = os.Stdout
+
+// phase tracks the progress of one step of the job, counted in values
+type phase struct {
+	name        string
+	done, total int64
+	started     time.Time // poll before the first value was counted
+}
+
+func (p *phase) update(done int64, prevPoll time.Time) {
+	if p.started.IsZero() && done > 0 {
+		p.started = prevPoll
+	}
+	p.done = done
+}
+
+func (p *phase) fraction() float64 {
+	if p.total == 0 {
+		return 1
+	}
+	return float64(p.done) / float64(p.total)
+}
+
+// rate in values per second since the phase started
+func (p *phase) rate(now time.Time) float64 {
+	elapsed := now.Sub(p.started).Seconds()
+	if p.started.IsZero() || elapsed <= 0 {
+		return 0
+	}
+	return float64(p.done) / elapsed
+}
+
+func (p *phase) eta(now time.Time) string {
+	if p.done >= p.total {
+		return "done"
+	}
+	r := p.rate(now)
+	if r == 0 {
+		return "--"
+	}
+	return time.Duration(float64(p.total-p.done) / r * float64(time.Second)).Round(time.Second).String()
+}
+
+// jobProgress is the aggregated status of all workers
+type jobProgress struct {
+	phases       []*phase // map, shuffle, write
+	bytesWritten int64
+	reducersDone int
+	reducers     int
+	unreachable  []string
+	polled       time.Time
+}
+
+func newJobProgress(total int64, reducers int) *jobProgress {
+	return &jobProgress{
+		phases: []*phase{
+			{name: "map", total: total},
+			{name: "shuffle", total: total},
+			{name: "write", total: total},
+		},
+		reducers: reducers,
+		polled:   time.Now(),
+	}
+}
+
+// setTotal updates the number of values every phase has to process
+func (jp *jobProgress) setTotal(total int64) {
+	for _, p := range jp.phases {
+		p.total = total
+	}
+}
+
+func (jp *jobProgress) update(mappers, reducers []*pb.GetStatusResponse, unreachable []string, now time.Time) {
+	var received, sent, written, bytes int64
+	done := 0
+	// duplicates dropped by a unique job count as shuffled and written
+	for _, s := range mappers {
+		if s == nil || s.JobId != jobID {
+			continue
+		}
+		received += s.ValuesReceived
+		sent += s.ValuesSent + s.Duplicates
+		written += s.Duplicates
+	}
+	for _, s := range reducers {
+		if s == nil || s.JobId != jobID {
+			continue
+		}
+		written += s.ValuesWritten + s.Duplicates
+		bytes += s.BytesWritten
+		if s.Done {
+			done++
+		}
+	}
+	jp.phases[0].update(received, jp.polled)
+	jp.phases[1].update(sent, jp.polled)
+	jp.phases[2].update(written, jp.polled)
+	jp.polled = now
+	jp.bytesWritten = bytes
+	jp.reducersDone = done
+	jp.unreachable = unreachable
+}
+
+func (jp *jobProgress) finished() bool {
+	return jp.reducersDone == jp.reducers
+}
+
+// current is the first phase that is not complete
+func (jp *jobProgress) current() *phase {
+	for _, p := range jp.phases {
+		if p.done < p.total {
+			return p
+		}
+	}
+	return jp.phases[len(jp.phases)-1]
+}
+
+// render writes the progress view and returns the number of lines written
+func (jp *jobProgress) render(w io.Writer, now time.Time) int {
+	lines := 0
+	for _, p := range jp.phases {
+		filled := int(p.fraction() * barWidth)
+		fmt.Fprintf(w, "\033[2K%-8s [%s%s] %5.1f%%  %d/%d values  %s/s  ETA %s\n",
+			p.name, strings.Repeat("#", filled), strings.Repeat(".", barWidth-filled),
+			p.fraction()*100, p.done, p.total, humanCount(p.rate(now)), p.eta(now))
+		lines++
+	}
+	fmt.Fprintf(w, "\033[2Kreducers %d/%d done, %s written\n", jp.reducersDone, jp.reducers, humanBytes(jp.bytesWritten))
+	lines++
+	if len(jp.unreachable) > 0 {
+		fmt.Fprintf(w, "\033[2Kunreachable: %s\n", strings.Join(jp.unreachable, ", "))
+		lines++
+	}
+	return lines
+}
+
+func (jp *jobProgress) log() {
+	p := jp.current()
+	now := time.Now()
+	args := []any{"phase", p.name, "percent", fmt.Sprintf("%.1f", p.fraction()*100),
+		"values", p.done, "total", p.total, "rate", int64(p.rate(now)), "eta", p.eta(now),
+		"reducers_done", jp.reducersDone, "bytes_written", jp.bytesWritten}
+	if len(jp.unreachable) > 0 {
+		args = append(args, "unreachable", jp.unreachable)
+	}
+	logger.Info("Progress", args...)
+}
+
+// trackProgress polls the status of every worker and reports it until all reducers are done,
+// then returns the final status of each mapper and reducer. It fails as soon as a worker reports
+// an error, or when a worker has not answered maxMissedPolls polls in a row. total is read at every poll, as it is only
+// estimated until mappers reading a shared input report how many values they read.
+// When progressOut is a terminal the view is redrawn in place, otherwise it is logged every logInterval.
+func trackProgress(ctx context.Context, mapperAddrs, reducerAddrs []string, total *atomic.Int64) (mappers, reducers []*pb.GetStatusResponse, err error) {
+	addrs := append(append([]string(nil), mapperAddrs...), reducerAddrs...)
+	clients := make([]pb.WorkerServiceClient, len(addrs))
+	for i, addr := range addrs {
+		client, conn, err := dialWorker(addr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("connect to %s: %w", addr, err)
+		}
+		defer conn.Close()
+		clients[i] = client
+	}
+
+	jp := newJobProgress(total.Load(), len(reducerAddrs))
+	tty := isTerminal(progressOut)
+	drawn := 0
+	if tty {
+		// keep log records above the view
+		logging.Around(func() {
+			if drawn > 0 {
+				fmt.Fprintf(progressOut, "\033[%dA\033[J", drawn)
+				drawn = 0
+			}
+		}, func() {
+			drawn = jp.render(progressOut, time.Now())
+		})
+		defer logging.Around(nil, nil)
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	lastLog := time.Now()
+	missed := make([]int, len(addrs))
+	for {
+		statuses, unreachable := pollStatus(ctx, addrs, clients)
+		now := time.Now()
+		for i, s := range statuses {
+			if s == nil {
+				missed[i]++
+				if missed[i] >= maxMissedPolls {
+					return nil, nil, fmt.Errorf("worker %s did not answer %d polls in a row", addrs[i], missed[i])
+				}
+				continue
+			}
+			missed[i] = 0
+			if s.JobId == jobID && s.Error != "" {
+				return nil, nil, fmt.Errorf("%s %s failed: %s", strings.ToLower(s.Role), addrs[i], s.Error)
+			}
+		}
+		dash.update(addrs, statuses, now)
+		logging.Exclusive(func() {
+			jp.setTotal(total.Load())
+			jp.update(statuses[:len(mapperAddrs)], statuses[len(mapperAddrs):], unreachable, now)
+			if tty {
+				if drawn > 0 {
+					fmt.Fprintf(progressOut, "\033[%dA\033[J", drawn)
+				}
+				drawn = jp.render(progressOut, now)
+			}
+		})
+		if !tty && (now.Sub(lastLog) >= logInterval || jp.finished()) {
+			jp.log()
+			lastLog = now
+		}
+		if jp.finished() {
+			return statuses[:len(mapperAddrs)], statuses[len(mapperAddrs):], nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollStatus queries all workers concurrently, a nil status means the worker did not answer
+func pollStatus(ctx context.Context, addrs []string, clients []pb.WorkerServiceClient) ([]*pb.GetStatusResponse, []string) {
+	statuses := make([]*pb.GetStatusResponse, len(clients))
+	var wg sync.WaitGroup
+	for i, client := range clients {
+		wg.Add(1)
+		go func(i int, client pb.WorkerServiceClient) {
+			defer wg.Done()
+			callCtx, cancel := context.WithTimeout(ctx, pollInterval)
+			defer cancel()
+			s, err := client.GetStatus(callCtx, &pb.GetStatusRequest{})
+			if err == nil {
+				statuses[i] = s
+			}
+		}(i, client)
+	}
+	wg.Wait()
+	var unreachable []string
+	for i, s := range statuses {
+		if s == nil {
+			unreachable = append(unreachable, addrs[i])
+		}
+	}
+	return statuses, unreachable
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func humanCount(n float64) string {
+	switch {
+	case n >= 1e9:
+		return fmt.Sprintf("%.1fG", n/1e9)
+	case n >= 1e6:
+		return fmt.Sprintf("%.1fM", n/1e6)
+	case n >= 1e3:
+		return fmt.Sprintf("%.1fk", n/1e3)
+	}
+	return fmt.Sprintf("%.0f", n)
+}
+
+func humanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,113 @@
+package master
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"mapreduce/ioformat"
+	pb "mapreduce/proto"
+	"mapreduce/tracing"
+)
+
+// quantile is a requested quantile and where it was found
+type quantile struct {
+	q       float64
+	rank    int64 // 0-based rank among all values
+	reducer int   // index of the reducer holding the rank
+	local   int64 // rank within the values of the reducer
+	value   int64 // key of the value at rank
+}
+
+// parseQuantiles parses a comma-separated list of quantiles between 0 and 1
+func parseQuantiles(s string) ([]float64, error) {
+	var qs []float64
+	for _, f := range strings.Split(s, ",") {
+		q, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+		if err != nil || math.IsNaN(q) || q < 0 || q > 1 {
+			return nil, fmt.Errorf("invalid quantile %q, expected a number between 0 and 1", f)
+		}
+		qs = append(qs, q)
+	}
+	return qs, nil
+}
+
+// locateQuantiles finds the reducer holding the value of each quantile, from the number of values of
+// each reducer in interval order. The quantile q of n values is the value of rank ceil(q*n), counted
+// from 1 like the nearest-rank method, so that 0 is the smallest value and 1 the largest.
+func locateQuantiles(qs []float64, counts []int64) []quantile {
+	var n int64
+	for _, c := range counts {
+		n += c
+	}
+	quantiles := make([]quantile, len(qs))
+	for i, q := range qs {
+		rank := int64(math.Ceil(q*float64(n))) - 1
+		rank = max(0, min(rank, n-1))
+		quantiles[i] = quantile{q: q, rank: rank}
+		start := int64(0)
+		for r, c := range counts {
+			if rank < start+c {
+				quantiles[i].reducer, quantiles[i].local = r, rank-start
+				break
+			}
+			start += c
+		}
+	}
+	return quantiles
+}
+
+// queryQuantiles reads the value of each quantile from the sorted values retained by the reducers,
+// with one call to each reducer holding some of them
+func queryQuantiles(ctx context.Context, reducerAddrs []string, quantiles []quantile) error {
+	for r, addr := range reducerAddrs {
+		var ranks []int64
+		var held []int
+		for i, q := range quantiles {
+			if q.reducer == r {
+				ranks = append(ranks, q.local)
+				held = append(held, i)
+			}
+		}
+		if len(ranks) == 0 {
+			continue
+		}
+		values, err := queryRanks(ctx, addr, ranks)
+		if err != nil {
+			return fmt.Errorf("query ranks of %s: %w", addr, err)
+		}
+		if len(values) != len(ranks) {
+			return fmt.Errorf("query ranks of %s: %d values for %d ranks", addr, len(values), len(ranks))
+		}
+		for j, i := range held {
+			quantiles[i].value = values[j]
+		}
+	}
+	return nil
+}
+
+func queryRanks(ctx context.Context, addr string, ranks []int64) ([]int64, error) {
+	ctx, span := tracing.Start(ctx, "query ranks", "worker", addr, "ranks", len(ranks))
+	defer span.End()
+	client, conn, err := dialWorker(addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	resp, err := client.QueryRanks(ctx, &pb.QueryRanksRequest{JobId: jobID, Ranks: ranks})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Values, nil
+}
+
+// formatQuantiles returns a line per quantile with its value, separated by a comma
+func formatQuantiles(quantiles []quantile, t ioformat.Type) string {
+	var b strings.Builder
+	for _, q := range quantiles {
+		fmt.Fprintf(&b, "%s,%s\n", strconv.FormatFloat(q.q, 'g', -1, 64), t.FormatKey(q.value))
+	}
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package master
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseQuantiles(t *testing.T) {
+	qs, err := parseQuantiles("0, 0.5,0.99,1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []float64{0, 0.5, 0.99, 1}; !slices.Equal(qs, want) {
+		t.Errorf("parseQuantiles = %v, want %v", qs, want)
+	}
+	for _, s := range []string{"", "x", "-0.1", "1.5", "NaN", "nan", "Inf", "0.5,"} {
+		if qs, err := parseQuantiles(s); err == nil {
+			t.Errorf("parseQuantiles(%q) = %v, want an error", s, qs)
+		}
+	}
+}
+
+func TestLocateQuantiles(t *testing.T) {
+	// 10 values: ranks 0-2 on reducer 0, none on reducer 1, 3-6 on reducer 2, 7-9 on reducer 3
+	counts := []int64{3, 0, 4, 3}
+	tests := []struct {
+		name    string
+		q       float64
+		rank    int64
+		reducer int
+		local   int64
+	}{
+		{"smallest value", 0, 0, 0, 0},
+		{"rank 1", 0.1, 0, 0, 0},
+		{"rank 2", 0.11, 1, 0, 1},
+		{"last value of a reducer", 0.3, 2, 0, 2},
+		{"first value after an empty reducer", 0.31, 3, 2, 0},
+		{"last value before a boundary", 0.7, 6, 2, 3},
+		{"first value after a boundary", 0.71, 7, 3, 0},
+		{"largest value", 1, 9, 3, 2},
+	}
+	qs := make([]float64, len(tests))
+	for i, tt := range tests {
+		qs[i] = tt.q
+	}
+	got := locateQuantiles(qs, counts)
+	for i, tt := range tests {
+		want := quantile{q: tt.q, rank: tt.rank, reducer: tt.reducer, local: tt.local}
+		if got[i] != want {
+			t.Errorf("%s: locateQuantiles(%v) = %+v, want %+v", tt.name, tt.q, got[i], want)
+		}
+	}
+}
+
+func TestLocateQuantilesSingleValue(t *testing.T) {
+	for _, q := range []float64{0, 0.5, 1} {
+		got := locateQuantiles([]float64{q}, []int64{0, 1, 0})[0]
+		if got.rank != 0 || got.reducer != 1 || got.local != 0 {
+			t.Errorf("locateQuantiles(%v) = %+v, want rank 0 on reducer 1", q, got)
+		}
+	}
+}
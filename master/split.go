@@ -0,0 +1,318 @@
+package master
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"mapreduce/ioformat"
+	"mapreduce/keys"
+	pb "mapreduce/proto"
+	"mapreduce/tracing"
+)
+
+const (
+	// lineBuffer is the initial buffer of the text lines read while sampling or aligning splits,
+	// it grows for longer lines
+	lineBuffer = 128
+	// pilotSamples are drawn to estimate the number of text records before sizing the sample
+	pilotSamples = 1000
+	// maxSamples caps the sample of a shared input, every sample is a random read
+	maxSamples = 1 << 20
+)
+
+// inputFile is one input file opened by the master in shared input mode, where mappers read it themselves
+type inputFile struct {
+	f      *os.File
+	path   string // absolute, so that mappers resolve it regardless of their working directory
+	size   int64
+	start  int64 // offset of the file in the concatenation of all input files
+	format ioformat.Format
+}
+
+// sharedInput is the concatenation of the input files, split and sampled by byte offset
+type sharedInput struct {
+	files  []*inputFile
+	size   int64
+	parser keys.Parser // parses text lines as values or records
+}
+
+// openShared opens the input files for splitting. Compressed files cannot be split.
+func openShared(paths []string, format ioformat.Format, parser keys.Parser) (*sharedInput, error) {
+	in := &sharedInput{parser: parser}
+	for _, path := range paths {
+		if path == "-" {
+			in.Close()
+			return nil, errors.New("stdin cannot be read by mappers, disable shared input")
+		}
+		file, err := openInputFile(path, format, keys.Numeric(parser.Type))
+		if err != nil {
+			in.Close()
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		file.start = in.size
+		in.size += file.size
+		in.files = append(in.files, file)
+	}
+	return in, nil
+}
+
+func openInputFile(path string, format ioformat.Format, t ioformat.Type) (*inputFile, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(abs)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	head := make([]byte, 4096)
+	n, err := f.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		f.Close()
+		return nil, err
+	}
+	head = head[:n]
+	if ioformat.IsGzip(path, head) {
+		f.Close()
+		return nil, errors.New("compressed input cannot be split, decompress it or disable shared input")
+	}
+	if format == ioformat.Auto {
+		format = ioformat.Detect(head, t)
+	}
+	return &inputFile{f: f, path: abs, size: info.Size(), format: format}, nil
+}
+
+func (in *sharedInput) Close() error {
+	for _, file := range in.files {
+		file.f.Close()
+	}
+	return nil
+}
+
+// locate returns the file holding the byte at global offset off
+func (in *sharedInput) locate(off int64) *inputFile {
+	i := sort.Search(len(in.files), func(i int) bool {
+		return in.files[i].start+in.files[i].size > off
+	})
+	return in.files[i]
+}
+
+// splits divides the input into n byte ranges of about the same size, one per mapper. A range may
+// cover several files. Text boundaries are moved after a newline, binary ones to a multiple
+// of 8 bytes, so that no record spans two ranges. Some ranges may be empty.
+func (in *sharedInput) splits(n int) ([][]*pb.InputSplit, error) {
+	bounds := make([]int64, n+1)
+	bounds[n] = in.size
+	for i := 1; i < n; i++ {
+		b := in.size * int64(i) / int64(n)
+		if b < in.size {
+			file := in.locate(b)
+			local, err := file.align(b - file.start)
+			if err != nil {
+				return nil, err
+			}
+			b = file.start + local
+		}
+		if b < bounds[i-1] {
+			b = bounds[i-1]
+		}
+		bounds[i] = b
+	}
+	splits := make([][]*pb.InputSplit, n)
+	for i := range splits {
+		lo, hi := bounds[i], bounds[i+1]
+		for _, file := range in.files {
+			start, end := max(lo, file.start), min(hi, file.start+file.size)
+			if start >= end {
+				continue
+			}
+			splits[i] = append(splits[i], &pb.InputSplit{
+				Path:     file.path,
+				Offset:   start - file.start,
+				Length:   end - start,
+				Format:   pb.DataFormat(file.format),
+				Position: start,
+			})
+		}
+	}
+	return splits, nil
+}
+
+// align returns the offset of the first record starting at or after off
+func (file *inputFile) align(off int64) (int64, error) {
+	if file.format != ioformat.Text {
+		return off - off%8, nil
+	}
+	if off == 0 {
+		return 0, nil
+	}
+	skipped, err := file.lineAt(off - 1)
+	switch {
+	case err == io.EOF:
+		return file.size, nil
+	case err != nil:
+		return 0, fmt.Errorf("%s: no line end after offset %d: %w", file.path, off, err)
+	}
+	return off - 1 + int64(len(skipped)), nil
+}
+
+// lineAt reads the text line starting at off, with its line end. It returns io.EOF if the file ends first.
+func (file *inputFile) lineAt(off int64) ([]byte, error) {
+	r := bufio.NewReaderSize(io.NewSectionReader(file.f, off, file.size-off), lineBuffer)
+	return r.ReadBytes('\n')
+}
+
+// sample reads values at random offsets, so that each file is sampled in proportion to its size.
+// It returns about 1% of the records, along with the number of records, exact for binary
+// files and estimated from the average length of the sampled lines for text files.
+func (in *sharedInput) sample() ([]*pb.Record, int64, error) {
+	var binaryRecords, textBytes int64
+	for _, file := range in.files {
+		if file.format == ioformat.Text {
+			textBytes += file.size
+		} else {
+			binaryRecords += file.size / 8
+		}
+	}
+	if in.size == 0 {
+		return nil, 0, nil
+	}
+
+	var samples []*pb.Record
+	var textSamples, lineBytes int64
+	estimate := func() int64 {
+		if textSamples == 0 {
+			return binaryRecords
+		}
+		return binaryRecords + textBytes*textSamples/lineBytes
+	}
+	target := pilotSamples
+	for attempts := 0; len(samples) < target; attempts++ {
+		if attempts > 10*target && len(samples) == 0 {
+			return nil, 0, nil
+		}
+		off := mathrand.Int63n(in.size)
+		file := in.locate(off)
+		v, length, ok, err := file.sampleAt(off-file.start, in.parser)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !ok {
+			continue
+		}
+		samples = append(samples, v)
+		if file.format == ioformat.Text {
+			textSamples++
+			lineBytes += length
+		}
+		if len(samples) == pilotSamples {
+			target = sampleCount(estimate())
+		}
+	}
+	if len(samples) > target {
+		samples = samples[:target]
+	}
+	return samples, estimate(), nil
+}
+
+// sampleAt parses the first record starting at or after off, wrapping to the start of the file,
+// and returns its length in bytes. Binary values and text values are returned as records holding
+// only a key. ok is false for blank lines and truncated records.
+func (file *inputFile) sampleAt(off int64, parser keys.Parser) (r *pb.Record, length int64, ok bool, err error) {
+	start, err := file.align(off)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	if start >= file.size {
+		start = 0
+	}
+	switch file.format {
+	case ioformat.BinaryLE, ioformat.BinaryBE:
+		buf := make([]byte, 8)
+		if _, err := file.f.ReadAt(buf, start); err != nil {
+			if err == io.EOF {
+				return nil, 0, false, nil
+			}
+			return nil, 0, false, err
+		}
+		t := keys.Numeric(parser.Type)
+		if file.format == ioformat.BinaryBE {
+			return &pb.Record{Key: t.Key(binary.BigEndian.Uint64(buf))}, 8, true, nil
+		}
+		return &pb.Record{Key: t.Key(binary.LittleEndian.Uint64(buf))}, 8, true, nil
+	}
+	line, err := file.lineAt(start)
+	if err != nil && err != io.EOF {
+		return nil, 0, false, err
+	}
+	if len(bytes.TrimSpace(line)) == 0 {
+		return nil, 0, false, nil
+	}
+	r, err = parser.Record(bytes.TrimRight(line, "\r\n"))
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("%s: line at offset %d: %w", file.path, start, err)
+	}
+	return r, int64(len(line)), true, nil
+}
+
+// sampleCount is 1% of total, bounded to [1, maxSamples]
+func sampleCount(total int64) int {
+	n := total / 100
+	if n < 1 {
+		n = 1
+	}
+	if n > maxSamples {
+		n = maxSamples
+	}
+	return int(n)
+}
+
+// assignSplits has a mapper read and map its splits of the input, and returns the number of values it read.
+// In record mode the mapper reads the lines of its splits as records keyed by key.
+func assignSplits(ctx context.Context, addr string, splits []*pb.InputSplit, parser keys.Parser) (int64, error) {
+	var bytes int64
+	for _, s := range splits {
+		bytes += s.Length
+	}
+	ctx, span := tracing.StartTrack(ctx, "assign splits", "worker", addr, "splits", len(splits), "bytes", bytes)
+	defer span.End()
+	client, conn, err := dialWorker(addr)
+	if err != nil {
+		return 0, fmt.Errorf("connect to mapper %s: %w", addr, err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logger.Warn("Failed to close connection", "worker", addr, "error", err)
+		}
+	}()
+	resp, err := client.AssignSplit(ctx, &pb.AssignSplitRequest{
+		Splits:    splits,
+		KeyField:  int32(parser.Field.Field),
+		Delimiter: parser.Field.Delimiter,
+		KeyType:   parser.Type,
+		SortKeys:  parser.Columns,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("assign splits to mapper %s: %w", addr, err)
+	}
+	span.SetAttrs("values", resp.Values)
+	chunkValuesSent.With(addr).Add(float64(resp.Values))
+	dash.setChunk(addr, resp.Values)
+	logger.Info("Mapper read its splits", "phase", "map", "worker", addr,
+		"splits", len(splits), "bytes", bytes, "values", resp.Values)
+	return resp.Values, nil
+}
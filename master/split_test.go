@@ -0,0 +1,204 @@
+package master
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mapreduce/ioformat"
+	"mapreduce/keys"
+	pb "mapreduce/proto"
+)
+
+var int64Parser = keys.Parser{Type: pb.KeyType_KEY_INT64}
+
+// writeFiles writes each content to a file of a temporary directory and returns their paths
+func writeFiles(t *testing.T, contents ...[]byte) []string {
+	t.Helper()
+	dir := t.TempDir()
+	paths := make([]string, len(contents))
+	for i, c := range contents {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("in-%d", i))
+		if err := os.WriteFile(paths[i], c, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return paths
+}
+
+func binaryValues(vs ...int64) []byte {
+	b := make([]byte, 0, 8*len(vs))
+	for _, v := range vs {
+		b = binary.LittleEndian.AppendUint64(b, uint64(v))
+	}
+	return b
+}
+
+// checkSplits checks that the splits of each file cover it exactly once, in order, and that every
+// split starts at a record boundary, so that each record lands in exactly one split
+func checkSplits(t *testing.T, in *sharedInput, contents [][]byte, splits [][]*pb.InputSplit, n int) {
+	t.Helper()
+	if len(splits) != n {
+		t.Fatalf("splits(%d) returned %d mappers", n, len(splits))
+	}
+	covered := make([]int64, len(in.files))
+	var position int64
+	for m, mapper := range splits {
+		for _, s := range mapper {
+			i := 0
+			for in.files[i].path != s.Path {
+				i++
+			}
+			file, content := in.files[i], contents[i]
+			if s.Offset != covered[i] {
+				t.Fatalf("splits(%d): mapper %d starts %s at %d, want %d", n, m, s.Path, s.Offset, covered[i])
+			}
+			if s.Length <= 0 {
+				t.Fatalf("splits(%d): mapper %d has an empty range of %s", n, m, s.Path)
+			}
+			if s.Position != file.start+s.Offset || s.Position != position {
+				t.Fatalf("splits(%d): mapper %d position %d, want %d", n, m, s.Position, position)
+			}
+			switch file.format {
+			case ioformat.Text:
+				if s.Offset > 0 && content[s.Offset-1] != '\n' {
+					t.Fatalf("splits(%d): mapper %d starts %s mid-line at %d", n, m, s.Path, s.Offset)
+				}
+			default:
+				if s.Offset%8 != 0 {
+					t.Fatalf("splits(%d): mapper %d starts %s mid-value at %d", n, m, s.Path, s.Offset)
+				}
+			}
+			if s.Format != pb.DataFormat(file.format) {
+				t.Fatalf("splits(%d): format %v, want %v", n, s.Format, file.format)
+			}
+			covered[i] += s.Length
+			position += s.Length
+		}
+	}
+	for i, file := range in.files {
+		if covered[i] != file.size {
+			t.Fatalf("splits(%d) cover %d bytes of %s, want %d", n, covered[i], file.path, file.size)
+		}
+	}
+}
+
+func TestSplits(t *testing.T) {
+	long := bytes.Repeat([]byte("7"), 100)
+	tests := []struct {
+		name     string
+		contents [][]byte
+	}{
+		{"one text file", [][]byte{[]byte("1\n22\n333\n4444\n55555\n\n7\n")}},
+		{"no final newline", [][]byte{[]byte("10\n20\n30")}},
+		{"long line", [][]byte{append(append([]byte("1\n"), long...), "\n2\n3\n"...)}},
+		{"several text files", [][]byte{[]byte("1\n2\n3\n"), {}, []byte("44\n55\n"), []byte("666\n")}},
+		{"one binary file", [][]byte{binaryValues(1, 2, 3, 4, 5, 6, 7, 8, 9)}},
+		{"several binary files", [][]byte{binaryValues(1, 2), binaryValues(3), binaryValues(4, 5, 6, 7)}},
+		{"text and binary files", [][]byte{[]byte("1\n2\n3\n"), binaryValues(-1, 0x7f, 3)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in, err := openShared(writeFiles(t, tt.contents...), ioformat.Auto, int64Parser)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer in.Close()
+			// more mappers than records leaves some of them without a split
+			for n := 1; n <= 40; n++ {
+				splits, err := in.splits(n)
+				if err != nil {
+					t.Fatal(err)
+				}
+				checkSplits(t, in, tt.contents, splits, n)
+			}
+		})
+	}
+}
+
+func TestSplitsCrossFiles(t *testing.T) {
+	contents := [][]byte{[]byte("1\n2\n"), []byte("3\n4\n5\n6\n7\n8\n")}
+	in, err := openShared(writeFiles(t, contents...), ioformat.Text, int64Parser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	splits, err := in.splits(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSplits(t, in, contents, splits, 2)
+	// 16 bytes split at 8: the first mapper reads all of the first file and two lines of the second
+	if len(splits[0]) != 2 || splits[0][1].Offset != 0 || splits[0][1].Length != 4 {
+		t.Errorf("first mapper splits = %v, want the first file and 4 bytes of the second", splits[0])
+	}
+}
+
+func TestAlign(t *testing.T) {
+	in, err := openShared(writeFiles(t, []byte("10\n200\n\n33"), binaryValues(1, 2, 3)), ioformat.Auto, int64Parser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	text, bin := in.files[0], in.files[1]
+	for _, tt := range []struct {
+		file    *inputFile
+		off     int64
+		aligned int64
+	}{
+		{text, 0, 0},
+		{text, 1, 3},
+		{text, 3, 3},
+		{text, 4, 7},
+		{text, 7, 7},
+		{text, 8, 8},
+		{text, 9, 10}, // inside the last line, which has no newline
+		{bin, 0, 0},
+		{bin, 7, 0},
+		{bin, 8, 8},
+		{bin, 23, 16},
+	} {
+		got, err := tt.file.align(tt.off)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.aligned {
+			t.Errorf("%v align(%d) = %d, want %d", tt.file.format, tt.off, got, tt.aligned)
+		}
+	}
+}
+
+func TestSampleAt(t *testing.T) {
+	in, err := openShared(writeFiles(t, []byte("10\n\n30\n"), append(binaryValues(1, 2), 0, 0, 0)), ioformat.Auto, int64Parser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	text, bin := in.files[0], in.files[1]
+	for _, tt := range []struct {
+		file   *inputFile
+		off    int64
+		key    int64
+		length int64
+		ok     bool
+	}{
+		{text, 0, 10, 3, true},
+		{text, 2, 0, 0, false}, // blank line
+		{text, 4, 30, 3, true},
+		{text, 5, 10, 3, true}, // no line starts after offset 5: wraps to the first
+		{bin, 0, 1, 8, true},
+		{bin, 9, 2, 8, true},
+		{bin, 17, 0, 0, false}, // truncated value
+	} {
+		r, length, ok, err := tt.file.sampleAt(tt.off, int64Parser)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != tt.ok || ok && (r.Key != tt.key || length != tt.length) {
+			t.Errorf("%v sampleAt(%d) = %v, %d, %v, want key %d, %d, %v", tt.file.format, tt.off, r, length, ok, tt.key, tt.length, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"path"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	serverHandled = NewHistogramVec("mapreduce_grpc_server_handling_seconds",
+		"Latency of RPCs handled by this process.", nil, "method", "code")
+	clientHandled = NewHistogramVec("mapreduce_grpc_client_handling_seconds",
+		"Latency of RPCs issued by this process, until the response is received.", nil, "method", "code")
+)
+
+// UnaryServerInterceptor records the latency and status code of every handled RPC.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		serverHandled.With(path.Base(info.FullMethod), status.Code(err).String()).Observe(time.Since(start).Seconds())
+		return resp, err
+	}
+}
+
+// UnaryClientInterceptor records the latency and status code of every issued RPC.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		clientHandled.With(path.Base(method), status.Code(err).String()).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// StreamServerInterceptor records the duration and status code of every handled stream.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		serverHandled.With(path.Base(info.FullMethod), status.Code(err).String()).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// StreamClientInterceptor records the duration and status code of every issued stream, until the
+// last message is received or the stream fails.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			clientHandled.With(path.Base(method), status.Code(err).String()).Observe(time.Since(start).Seconds())
+			return nil, err
+		}
+		return &timedStream{ClientStream: cs, method: path.Base(method), start: start}, nil
+	}
+}
+
+// timedStream observes the duration of a client stream once it ends
+type timedStream struct {
+	grpc.ClientStream
+	method string
+	start  time.Time
+	once   sync.Once
+}
+
+func (s *timedStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.once.Do(func() {
+			code := status.Code(err)
+			if err == io.EOF {
+				code = status.Code(nil)
+			}
+			clientHandled.With(s.method, code.String()).Observe(time.Since(s.start).Seconds())
+		})
+	}
+	return err
+}
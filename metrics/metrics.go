@@ -0,0 +1,303 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the histogram upper bounds in seconds, matching the Prometheus client defaults.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// all registered metric families, in registration order
+var (
+	registryMu sync.Mutex
+	registry   []family
+)
+
+type family interface {
+	write(w io.Writer)
+}
+
+func register(f family) {
+	registryMu.Lock()
+	registry = append(registry, f)
+	registryMu.Unlock()
+}
+
+// vec holds the series of one metric family, keyed by their label values.
+type vec[T any] struct {
+	name, help, kind string
+	labels           []string
+	newSeries        func() *T
+
+	mu     sync.Mutex
+	series map[string]*T
+	values map[string][]string
+}
+
+func newVec[T any](name, help, kind string, labels []string, newSeries func() *T) *vec[T] {
+	return &vec[T]{
+		name:      name,
+		help:      help,
+		kind:      kind,
+		labels:    labels,
+		newSeries: newSeries,
+		series:    make(map[string]*T),
+		values:    make(map[string][]string),
+	}
+}
+
+func (v *vec[T]) with(values ...string) *T {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metric %s: expected %d label values, got %d", v.name, len(v.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	s, ok := v.series[key]
+	if !ok {
+		s = v.newSeries()
+		v.series[key] = s
+		v.values[key] = append([]string(nil), values...)
+	}
+	return s
+}
+
+// each calls fn for every series sorted by label values, with the rendered label pairs.
+func (v *vec[T]) each(fn func(labels []string, s *T)) {
+	v.mu.Lock()
+	keys := make([]string, 0, len(v.series))
+	for k := range v.series {
+		keys = append(keys, k)
+	}
+	v.mu.Unlock()
+	sort.Strings(keys)
+	for _, k := range keys {
+		v.mu.Lock()
+		s, values := v.series[k], v.values[k]
+		v.mu.Unlock()
+		pairs := make([]string, len(values))
+		for i, val := range values {
+			pairs[i] = v.labels[i] + "=" + quote(val)
+		}
+		fn(pairs, s)
+	}
+}
+
+func (v *vec[T]) header(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", v.name, escapeHelp(v.help), v.name, v.kind)
+}
+
+// Counter is a monotonically increasing value.
+type Counter struct {
+	mu  sync.Mutex
+	val float64
+}
+
+func (c *Counter) Add(delta float64) {
+	c.mu.Lock()
+	c.val += delta
+	c.mu.Unlock()
+}
+
+func (c *Counter) Inc() { c.Add(1) }
+
+func (c *Counter) get() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.val
+}
+
+type CounterVec struct {
+	*vec[Counter]
+}
+
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	v := &CounterVec{newVec(name, help, "counter", labels, func() *Counter { return &Counter{} })}
+	register(v)
+	return v
+}
+
+// NewCounter registers a counter without labels.
+func NewCounter(name, help string) *Counter {
+	return NewCounterVec(name, help).With()
+}
+
+func (v *CounterVec) With(values ...string) *Counter { return v.with(values...) }
+
+func (v *CounterVec) write(w io.Writer) {
+	v.header(w)
+	v.each(func(labels []string, c *Counter) {
+		fmt.Fprintf(w, "%s%s %s\n", v.name, labelSet(labels), formatFloat(c.get()))
+	})
+}
+
+// Gauge is a value that can go up and down.
+type Gauge struct {
+	mu  sync.Mutex
+	val float64
+}
+
+func (g *Gauge) Set(val float64) {
+	g.mu.Lock()
+	g.val = val
+	g.mu.Unlock()
+}
+
+func (g *Gauge) Add(delta float64) {
+	g.mu.Lock()
+	g.val += delta
+	g.mu.Unlock()
+}
+
+func (g *Gauge) get() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.val
+}
+
+type GaugeVec struct {
+	*vec[Gauge]
+}
+
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	v := &GaugeVec{newVec(name, help, "gauge", labels, func() *Gauge { return &Gauge{} })}
+	register(v)
+	return v
+}
+
+// NewGauge registers a gauge without labels.
+func NewGauge(name, help string) *Gauge {
+	return NewGaugeVec(name, help).With()
+}
+
+func (v *GaugeVec) With(values ...string) *Gauge { return v.with(values...) }
+
+func (v *GaugeVec) write(w io.Writer) {
+	v.header(w)
+	v.each(func(labels []string, g *Gauge) {
+		fmt.Fprintf(w, "%s%s %s\n", v.name, labelSet(labels), formatFloat(g.get()))
+	})
+}
+
+// Histogram counts observations into cumulative buckets.
+type Histogram struct {
+	bounds []float64
+
+	mu     sync.Mutex
+	counts []uint64 // one per bound, plus +Inf
+	sum    float64
+}
+
+func (h *Histogram) Observe(val float64) {
+	i := sort.SearchFloat64s(h.bounds, val)
+	h.mu.Lock()
+	h.counts[i]++
+	h.sum += val
+	h.mu.Unlock()
+}
+
+type HistogramVec struct {
+	*vec[Histogram]
+}
+
+// NewHistogramVec registers a histogram family, using DefaultBuckets if buckets is nil.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	v := &HistogramVec{newVec(name, help, "histogram", labels, func() *Histogram {
+		return &Histogram{bounds: buckets, counts: make([]uint64, len(buckets)+1)}
+	})}
+	register(v)
+	return v
+}
+
+// NewHistogram registers a histogram without labels.
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	return NewHistogramVec(name, help, buckets).With()
+}
+
+func (v *HistogramVec) With(values ...string) *Histogram { return v.with(values...) }
+
+func (v *HistogramVec) write(w io.Writer) {
+	v.header(w)
+	v.each(func(labels []string, h *Histogram) {
+		h.mu.Lock()
+		counts := append([]uint64(nil), h.counts...)
+		sum := h.sum
+		h.mu.Unlock()
+
+		var cumulative uint64
+		for i, bound := range h.bounds {
+			cumulative += counts[i]
+			le := append(labels[:len(labels):len(labels)], "le="+quote(formatFloat(bound)))
+			fmt.Fprintf(w, "%s_bucket%s %d\n", v.name, labelSet(le), cumulative)
+		}
+		cumulative += counts[len(h.bounds)]
+		le := append(labels[:len(labels):len(labels)], `le="+Inf"`)
+		fmt.Fprintf(w, "%s_bucket%s %d\n", v.name, labelSet(le), cumulative)
+		fmt.Fprintf(w, "%s_sum%s %s\n", v.name, labelSet(labels), formatFloat(sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", v.name, labelSet(labels), cumulative)
+	})
+}
+
+// WriteText writes every registered metric in the Prometheus text exposition format.
+func WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	registryMu.Lock()
+	families := append([]family(nil), registry...)
+	registryMu.Unlock()
+	for _, f := range families {
+		f.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Handler serves the registered metrics.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = WriteText(w)
+	})
+}
+
+// Serve exposes /metrics on addr. It blocks like http.ListenAndServe.
+func Serve(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	return http.ListenAndServe(addr, mux)
+}
+
+func labelSet(pairs []string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func quote(val string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(val) + `"`
+}
+
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+func formatFloat(val float64) string {
+	switch {
+	case math.IsInf(val, 1):
+		return "+Inf"
+	case math.IsInf(val, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(val, 'g', -1, 64)
+}
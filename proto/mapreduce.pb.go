@@ -20,36 +20,1347 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type OutputMode int32
+
+const (
+	// each reducer writes its own part file
+	OutputMode_OUTPUT_PARTS OutputMode = 0
+	// reducers keep their output until the master fetches it into a single file
+	OutputMode_OUTPUT_MERGED OutputMode = 1
+)
+
+// Enum value maps for OutputMode.
+var (
+	OutputMode_name = map[int32]string{
+		0: "OUTPUT_PARTS",
+		1: "OUTPUT_MERGED",
+	}
+	OutputMode_value = map[string]int32{
+		"OUTPUT_PARTS":  0,
+		"OUTPUT_MERGED": 1,
+	}
+)
+
+func (x OutputMode) Enum() *OutputMode {
+	p := new(OutputMode)
+	*p = x
+	return p
+}
+
+func (x OutputMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OutputMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_mapreduce_proto_enumTypes[0].Descriptor()
+}
+
+func (OutputMode) Type() protoreflect.EnumType {
+	return &file_proto_mapreduce_proto_enumTypes[0]
+}
+
+func (x OutputMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OutputMode.Descriptor instead.
+func (OutputMode) EnumDescriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{0}
+}
+
+// DataFormat is the encoding of numeric values. Binary values are the raw bits of the int64, uint64
+// or float64 chosen by key_type.
+type DataFormat int32
+
+const (
+	// one decimal value per line
+	DataFormat_TEXT DataFormat = 0
+	// raw 8-byte little-endian values
+	DataFormat_BINARY_LE DataFormat = 1
+	// raw 8-byte big-endian values
+	DataFormat_BINARY_BE DataFormat = 2
+)
+
+// Enum value maps for DataFormat.
+var (
+	DataFormat_name = map[int32]string{
+		0: "TEXT",
+		1: "BINARY_LE",
+		2: "BINARY_BE",
+	}
+	DataFormat_value = map[string]int32{
+		"TEXT":      0,
+		"BINARY_LE": 1,
+		"BINARY_BE": 2,
+	}
+)
+
+func (x DataFormat) Enum() *DataFormat {
+	p := new(DataFormat)
+	*p = x
+	return p
+}
+
+func (x DataFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DataFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_mapreduce_proto_enumTypes[1].Descriptor()
+}
+
+func (DataFormat) Type() protoreflect.EnumType {
+	return &file_proto_mapreduce_proto_enumTypes[1]
+}
+
+func (x DataFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DataFormat.Descriptor instead.
+func (DataFormat) EnumDescriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{1}
+}
+
+type BatchEncoding int32
+
+const (
+	// values in SendMappedDataRequest.values
+	BatchEncoding_BATCH_RAW BatchEncoding = 0
+	// zigzag varint deltas between consecutive sorted values
+	BatchEncoding_BATCH_DELTA_VARINT BatchEncoding = 1
+	// BATCH_DELTA_VARINT compressed with DEFLATE
+	BatchEncoding_BATCH_DELTA_VARINT_FLATE BatchEncoding = 2
+)
+
+// Enum value maps for BatchEncoding.
+var (
+	BatchEncoding_name = map[int32]string{
+		0: "BATCH_RAW",
+		1: "BATCH_DELTA_VARINT",
+		2: "BATCH_DELTA_VARINT_FLATE",
+	}
+	BatchEncoding_value = map[string]int32{
+		"BATCH_RAW":                0,
+		"BATCH_DELTA_VARINT":       1,
+		"BATCH_DELTA_VARINT_FLATE": 2,
+	}
+)
+
+func (x BatchEncoding) Enum() *BatchEncoding {
+	p := new(BatchEncoding)
+	*p = x
+	return p
+}
+
+func (x BatchEncoding) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BatchEncoding) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_mapreduce_proto_enumTypes[2].Descriptor()
+}
+
+func (BatchEncoding) Type() protoreflect.EnumType {
+	return &file_proto_mapreduce_proto_enumTypes[2]
+}
+
+func (x BatchEncoding) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BatchEncoding.Descriptor instead.
+func (BatchEncoding) EnumDescriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{2}
+}
+
+// JobKind is what a job computes from the sorted values
+type JobKind int32
+
+const (
+	// the sorted values or records
+	JobKind_JOB_SORT JobKind = 0
+	// each distinct value with its number of occurrences, in value order
+	JobKind_JOB_HISTOGRAM JobKind = 1
+	// the values at given ranks, reducers keep their sorted values for the master to query them
+	JobKind_JOB_QUANTILES JobKind = 2
+)
+
+// Enum value maps for JobKind.
+var (
+	JobKind_name = map[int32]string{
+		0: "JOB_SORT",
+		1: "JOB_HISTOGRAM",
+		2: "JOB_QUANTILES",
+	}
+	JobKind_value = map[string]int32{
+		"JOB_SORT":      0,
+		"JOB_HISTOGRAM": 1,
+		"JOB_QUANTILES": 2,
+	}
+)
+
+func (x JobKind) Enum() *JobKind {
+	p := new(JobKind)
+	*p = x
+	return p
+}
+
+func (x JobKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_mapreduce_proto_enumTypes[3].Descriptor()
+}
+
+func (JobKind) Type() protoreflect.EnumType {
+	return &file_proto_mapreduce_proto_enumTypes[3]
+}
+
+func (x JobKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobKind.Descriptor instead.
+func (JobKind) EnumDescriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{3}
+}
+
+type KeyType int32
+
+const (
+	// int64 values, or records keyed by Record.key
+	KeyType_KEY_INT64 KeyType = 0
+	// records keyed by Record.key_bytes, in byte order
+	KeyType_KEY_STRING KeyType = 1
+	// uint64 and float64 values and keys are carried as int64 keys that order like them,
+	// interval bounds included, see ioformat.Type
+	KeyType_KEY_UINT64  KeyType = 2
+	KeyType_KEY_FLOAT64 KeyType = 3
+	// records keyed by Record.key_bytes holding a big integer: a sign byte, 1 if negative,
+	// then the big-endian magnitude without leading zeros
+	KeyType_KEY_BIGINT KeyType = 4
+	// records keyed by Record.key_bytes holding the columns of AssignSplitRequest.sort_keys,
+	// encoded so that their byte order is the order of the columns
+	KeyType_KEY_COMPOSITE KeyType = 5
+)
+
+// Enum value maps for KeyType.
+var (
+	KeyType_name = map[int32]string{
+		0: "KEY_INT64",
+		1: "KEY_STRING",
+		2: "KEY_UINT64",
+		3: "KEY_FLOAT64",
+		4: "KEY_BIGINT",
+		5: "KEY_COMPOSITE",
+	}
+	KeyType_value = map[string]int32{
+		"KEY_INT64":     0,
+		"KEY_STRING":    1,
+		"KEY_UINT64":    2,
+		"KEY_FLOAT64":   3,
+		"KEY_BIGINT":    4,
+		"KEY_COMPOSITE": 5,
+	}
+)
+
+func (x KeyType) Enum() *KeyType {
+	p := new(KeyType)
+	*p = x
+	return p
+}
+
+func (x KeyType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (KeyType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_mapreduce_proto_enumTypes[4].Descriptor()
+}
+
+func (KeyType) Type() protoreflect.EnumType {
+	return &file_proto_mapreduce_proto_enumTypes[4]
+}
+
+func (x KeyType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use KeyType.Descriptor instead.
+func (KeyType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{4}
+}
+
+// SortKey is a column of a composite key
+type SortKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// 1-based field of the line
+	Field      int32   `protobuf:"varint,1,opt,name=field,proto3" json:"field,omitempty"`
+	Type       KeyType `protobuf:"varint,2,opt,name=type,proto3,enum=mapreduce.KeyType" json:"type,omitempty"`
+	Descending bool    `protobuf:"varint,3,opt,name=descending,proto3" json:"descending,omitempty"`
+}
+
+func (x *SortKey) Reset() {
+	*x = SortKey{}
+	mi := &file_proto_mapreduce_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SortKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SortKey) ProtoMessage() {}
+
+func (x *SortKey) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SortKey.ProtoReflect.Descriptor instead.
+func (*SortKey) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SortKey) GetField() int32 {
+	if x != nil {
+		return x.Field
+	}
+	return 0
+}
+
+func (x *SortKey) GetType() KeyType {
+	if x != nil {
+		return x.Type
+	}
+	return KeyType_KEY_INT64
+}
+
+func (x *SortKey) GetDescending() bool {
+	if x != nil {
+		return x.Descending
+	}
+	return false
+}
+
 type AssignRoleRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	IsMapper bool `protobuf:"varint,1,opt,name=is_mapper,json=isMapper,proto3" json:"is_mapper,omitempty"` // true if mapper, false if reducer
-	// List of reducer info if mapper
-	Reducers []*ReducerInfo `protobuf:"bytes,2,rep,name=reducers,proto3" json:"reducers,omitempty"`
-	// Number of mappers total (if reducer), so reducer knows how many done signals to wait for
-	TotalMappers int32 `protobuf:"varint,3,opt,name=total_mappers,json=totalMappers,proto3" json:"total_mappers,omitempty"`
-	// Interval for this reducer if is_mapper == false
-	IntervalStart int64 `protobuf:"varint,4,opt,name=interval_start,json=intervalStart,proto3" json:"interval_start,omitempty"`
-	IntervalEnd   int64 `protobuf:"varint,5,opt,name=interval_end,json=intervalEnd,proto3" json:"interval_end,omitempty"`
+	IsMapper bool `protobuf:"varint,1,opt,name=is_mapper,json=isMapper,proto3" json:"is_mapper,omitempty"` // true if mapper, false if reducer
+	// List of reducer info if mapper
+	Reducers []*ReducerInfo `protobuf:"bytes,2,rep,name=reducers,proto3" json:"reducers,omitempty"`
+	// Number of mappers total (if reducer), so reducer knows how many done signals to wait for
+	TotalMappers int32 `protobuf:"varint,3,opt,name=total_mappers,json=totalMappers,proto3" json:"total_mappers,omitempty"`
+	// Interval for this reducer if is_mapper == false
+	IntervalStart int64 `protobuf:"varint,4,opt,name=interval_start,json=intervalStart,proto3" json:"interval_start,omitempty"`
+	IntervalEnd   int64 `protobuf:"varint,5,opt,name=interval_end,json=intervalEnd,proto3" json:"interval_end,omitempty"`
+	// Token the mapper attaches to its calls to reducers (empty if auth is disabled)
+	MapperToken string `protobuf:"bytes,6,opt,name=mapper_token,json=mapperToken,proto3" json:"mapper_token,omitempty"`
+	// Identifier of the job, used to correlate logs across workers
+	JobId string `protobuf:"bytes,7,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// Rank of this reducer's interval, used to number its output part
+	Partition int32 `protobuf:"varint,8,opt,name=partition,proto3" json:"partition,omitempty"`
+	// Directory the reducer writes its output part to
+	OutputDir  string     `protobuf:"bytes,9,opt,name=output_dir,json=outputDir,proto3" json:"output_dir,omitempty"`
+	OutputMode OutputMode `protobuf:"varint,10,opt,name=output_mode,json=outputMode,proto3,enum=mapreduce.OutputMode" json:"output_mode,omitempty"`
+	// Encoding of the reducer output
+	OutputFormat DataFormat `protobuf:"varint,11,opt,name=output_format,json=outputFormat,proto3,enum=mapreduce.DataFormat" json:"output_format,omitempty"`
+	// Mapper: gzip-compress the values sent to reducers
+	CompressShuffle bool `protobuf:"varint,12,opt,name=compress_shuffle,json=compressShuffle,proto3" json:"compress_shuffle,omitempty"`
+	// Reducer: gzip-compress the output part
+	CompressOutput bool `protobuf:"varint,13,opt,name=compress_output,json=compressOutput,proto3" json:"compress_output,omitempty"`
+	// Mapper: preferred encoding of the batches sent to reducers
+	BatchEncoding BatchEncoding `protobuf:"varint,14,opt,name=batch_encoding,json=batchEncoding,proto3,enum=mapreduce.BatchEncoding" json:"batch_encoding,omitempty"`
+	// Reducer: the job sorts records, written back as lines, instead of values
+	Records bool `protobuf:"varint,15,opt,name=records,proto3" json:"records,omitempty"`
+	// Type of the keys, mappers route and reducers order records by it, reducers write values of this type
+	KeyType KeyType `protobuf:"varint,16,opt,name=key_type,json=keyType,proto3,enum=mapreduce.KeyType" json:"key_type,omitempty"`
+	// Name of the registered comparator ordering string keys, the byte order when empty
+	Comparator string `protobuf:"bytes,17,opt,name=comparator,proto3" json:"comparator,omitempty"`
+	// The job sorts in descending order, reducers hold the intervals from the highest to the lowest
+	Descending bool `protobuf:"varint,18,opt,name=descending,proto3" json:"descending,omitempty"`
+	// Keep one record of each key: mappers drop duplicates before shuffling, reducers while merging
+	Unique bool `protobuf:"varint,19,opt,name=unique,proto3" json:"unique,omitempty"`
+	// What the job computes, mappers count values and reducers add up counts in histogram jobs
+	Job JobKind `protobuf:"varint,20,opt,name=job,proto3,enum=mapreduce.JobKind" json:"job,omitempty"`
+}
+
+func (x *AssignRoleRequest) Reset() {
+	*x = AssignRoleRequest{}
+	mi := &file_proto_mapreduce_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignRoleRequest) ProtoMessage() {}
+
+func (x *AssignRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignRoleRequest.ProtoReflect.Descriptor instead.
+func (*AssignRoleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AssignRoleRequest) GetIsMapper() bool {
+	if x != nil {
+		return x.IsMapper
+	}
+	return false
+}
+
+func (x *AssignRoleRequest) GetReducers() []*ReducerInfo {
+	if x != nil {
+		return x.Reducers
+	}
+	return nil
+}
+
+func (x *AssignRoleRequest) GetTotalMappers() int32 {
+	if x != nil {
+		return x.TotalMappers
+	}
+	return 0
+}
+
+func (x *AssignRoleRequest) GetIntervalStart() int64 {
+	if x != nil {
+		return x.IntervalStart
+	}
+	return 0
+}
+
+func (x *AssignRoleRequest) GetIntervalEnd() int64 {
+	if x != nil {
+		return x.IntervalEnd
+	}
+	return 0
+}
+
+func (x *AssignRoleRequest) GetMapperToken() string {
+	if x != nil {
+		return x.MapperToken
+	}
+	return ""
+}
+
+func (x *AssignRoleRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *AssignRoleRequest) GetPartition() int32 {
+	if x != nil {
+		return x.Partition
+	}
+	return 0
+}
+
+func (x *AssignRoleRequest) GetOutputDir() string {
+	if x != nil {
+		return x.OutputDir
+	}
+	return ""
+}
+
+func (x *AssignRoleRequest) GetOutputMode() OutputMode {
+	if x != nil {
+		return x.OutputMode
+	}
+	return OutputMode_OUTPUT_PARTS
+}
+
+func (x *AssignRoleRequest) GetOutputFormat() DataFormat {
+	if x != nil {
+		return x.OutputFormat
+	}
+	return DataFormat_TEXT
+}
+
+func (x *AssignRoleRequest) GetCompressShuffle() bool {
+	if x != nil {
+		return x.CompressShuffle
+	}
+	return false
+}
+
+func (x *AssignRoleRequest) GetCompressOutput() bool {
+	if x != nil {
+		return x.CompressOutput
+	}
+	return false
+}
+
+func (x *AssignRoleRequest) GetBatchEncoding() BatchEncoding {
+	if x != nil {
+		return x.BatchEncoding
+	}
+	return BatchEncoding_BATCH_RAW
+}
+
+func (x *AssignRoleRequest) GetRecords() bool {
+	if x != nil {
+		return x.Records
+	}
+	return false
+}
+
+func (x *AssignRoleRequest) GetKeyType() KeyType {
+	if x != nil {
+		return x.KeyType
+	}
+	return KeyType_KEY_INT64
+}
+
+func (x *AssignRoleRequest) GetComparator() string {
+	if x != nil {
+		return x.Comparator
+	}
+	return ""
+}
+
+func (x *AssignRoleRequest) GetDescending() bool {
+	if x != nil {
+		return x.Descending
+	}
+	return false
+}
+
+func (x *AssignRoleRequest) GetUnique() bool {
+	if x != nil {
+		return x.Unique
+	}
+	return false
+}
+
+func (x *AssignRoleRequest) GetJob() JobKind {
+	if x != nil {
+		return x.Job
+	}
+	return JobKind_JOB_SORT
+}
+
+type AssignRoleResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *AssignRoleResponse) Reset() {
+	*x = AssignRoleResponse{}
+	mi := &file_proto_mapreduce_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignRoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignRoleResponse) ProtoMessage() {}
+
+func (x *AssignRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignRoleResponse.ProtoReflect.Descriptor instead.
+func (*AssignRoleResponse) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AssignRoleResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type SendChunkRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Values []int64 `protobuf:"varint,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	// set instead of values when sorting records
+	Records []*Record `protobuf:"bytes,2,rep,name=records,proto3" json:"records,omitempty"`
+}
+
+func (x *SendChunkRequest) Reset() {
+	*x = SendChunkRequest{}
+	mi := &file_proto_mapreduce_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendChunkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendChunkRequest) ProtoMessage() {}
+
+func (x *SendChunkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendChunkRequest.ProtoReflect.Descriptor instead.
+func (*SendChunkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SendChunkRequest) GetValues() []int64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *SendChunkRequest) GetRecords() []*Record {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+// Record is a line of the input sorted by a key extracted from it
+type Record struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key int64 `protobuf:"varint,1,opt,name=key,proto3" json:"key,omitempty"`
+	// the whole line, written back unchanged, empty when the line is key_bytes
+	Payload []byte `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	// key of the records of byte key types
+	KeyBytes []byte `protobuf:"bytes,3,opt,name=key_bytes,json=keyBytes,proto3" json:"key_bytes,omitempty"`
+	// position of the record in the input, orders records with equal keys
+	Seq int64 `protobuf:"varint,4,opt,name=seq,proto3" json:"seq,omitempty"`
+}
+
+func (x *Record) Reset() {
+	*x = Record{}
+	mi := &file_proto_mapreduce_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Record) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Record) ProtoMessage() {}
+
+func (x *Record) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Record.ProtoReflect.Descriptor instead.
+func (*Record) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Record) GetKey() int64 {
+	if x != nil {
+		return x.Key
+	}
+	return 0
+}
+
+func (x *Record) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Record) GetKeyBytes() []byte {
+	if x != nil {
+		return x.KeyBytes
+	}
+	return nil
+}
+
+func (x *Record) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+type SendChunkResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *SendChunkResponse) Reset() {
+	*x = SendChunkResponse{}
+	mi := &file_proto_mapreduce_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendChunkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendChunkResponse) ProtoMessage() {}
+
+func (x *SendChunkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendChunkResponse.ProtoReflect.Descriptor instead.
+func (*SendChunkResponse) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SendChunkResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type SendMappedDataRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Values         []int64 `protobuf:"varint,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	ReducerAddress string  `protobuf:"bytes,2,opt,name=reducer_address,json=reducerAddress,proto3" json:"reducer_address,omitempty"`
+	// Set instead of values when an encoding other than BATCH_RAW was negotiated
+	Encoded *EncodedBatch `protobuf:"bytes,3,opt,name=encoded,proto3" json:"encoded,omitempty"`
+	// Set instead of values when sorting records, batch encodings only apply to values
+	Records []*Record `protobuf:"bytes,4,rep,name=records,proto3" json:"records,omitempty"`
+	// Histogram jobs: occurrences of each of the values, which are distinct
+	Counts []int64 `protobuf:"varint,5,rep,packed,name=counts,proto3" json:"counts,omitempty"`
+}
+
+func (x *SendMappedDataRequest) Reset() {
+	*x = SendMappedDataRequest{}
+	mi := &file_proto_mapreduce_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendMappedDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendMappedDataRequest) ProtoMessage() {}
+
+func (x *SendMappedDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendMappedDataRequest.ProtoReflect.Descriptor instead.
+func (*SendMappedDataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SendMappedDataRequest) GetValues() []int64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *SendMappedDataRequest) GetReducerAddress() string {
+	if x != nil {
+		return x.ReducerAddress
+	}
+	return ""
+}
+
+func (x *SendMappedDataRequest) GetEncoded() *EncodedBatch {
+	if x != nil {
+		return x.Encoded
+	}
+	return nil
+}
+
+func (x *SendMappedDataRequest) GetRecords() []*Record {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *SendMappedDataRequest) GetCounts() []int64 {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+type EncodedBatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Encoding BatchEncoding `protobuf:"varint,1,opt,name=encoding,proto3,enum=mapreduce.BatchEncoding" json:"encoding,omitempty"`
+	// number of values in data
+	Count int64  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Data  []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *EncodedBatch) Reset() {
+	*x = EncodedBatch{}
+	mi := &file_proto_mapreduce_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EncodedBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncodedBatch) ProtoMessage() {}
+
+func (x *EncodedBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncodedBatch.ProtoReflect.Descriptor instead.
+func (*EncodedBatch) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *EncodedBatch) GetEncoding() BatchEncoding {
+	if x != nil {
+		return x.Encoding
+	}
+	return BatchEncoding_BATCH_RAW
+}
+
+func (x *EncodedBatch) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *EncodedBatch) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type AssignSplitRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// splits read and mapped together by the mapper
+	Splits []*InputSplit `protobuf:"bytes,1,rep,name=splits,proto3" json:"splits,omitempty"`
+	// when set, lines are read as records keyed by this 1-based field
+	KeyField  int32  `protobuf:"varint,2,opt,name=key_field,json=keyField,proto3" json:"key_field,omitempty"`
+	Delimiter string `protobuf:"bytes,3,opt,name=delimiter,proto3" json:"delimiter,omitempty"`
+	// type of the values or keys, lines are read as records keyed by the whole line for string keys
+	KeyType KeyType `protobuf:"varint,4,opt,name=key_type,json=keyType,proto3,enum=mapreduce.KeyType" json:"key_type,omitempty"`
+	// columns of the composite keys, with delimiter, when key_type is KEY_COMPOSITE
+	SortKeys []*SortKey `protobuf:"bytes,5,rep,name=sort_keys,json=sortKeys,proto3" json:"sort_keys,omitempty"`
+}
+
+func (x *AssignSplitRequest) Reset() {
+	*x = AssignSplitRequest{}
+	mi := &file_proto_mapreduce_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignSplitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignSplitRequest) ProtoMessage() {}
+
+func (x *AssignSplitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignSplitRequest.ProtoReflect.Descriptor instead.
+func (*AssignSplitRequest) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AssignSplitRequest) GetSplits() []*InputSplit {
+	if x != nil {
+		return x.Splits
+	}
+	return nil
+}
+
+func (x *AssignSplitRequest) GetKeyField() int32 {
+	if x != nil {
+		return x.KeyField
+	}
+	return 0
+}
+
+func (x *AssignSplitRequest) GetDelimiter() string {
+	if x != nil {
+		return x.Delimiter
+	}
+	return ""
+}
+
+func (x *AssignSplitRequest) GetKeyType() KeyType {
+	if x != nil {
+		return x.KeyType
+	}
+	return KeyType_KEY_INT64
+}
+
+func (x *AssignSplitRequest) GetSortKeys() []*SortKey {
+	if x != nil {
+		return x.SortKeys
+	}
+	return nil
+}
+
+type InputSplit struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// path of the input file, the same on the master and every mapper
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// byte range of the split, aligned to record boundaries
+	Offset int64      `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length int64      `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`
+	Format DataFormat `protobuf:"varint,4,opt,name=format,proto3,enum=mapreduce.DataFormat" json:"format,omitempty"`
+	// offset of the split in the concatenation of the input files, the base of the seq of its records
+	Position int64 `protobuf:"varint,5,opt,name=position,proto3" json:"position,omitempty"`
+}
+
+func (x *InputSplit) Reset() {
+	*x = InputSplit{}
+	mi := &file_proto_mapreduce_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InputSplit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InputSplit) ProtoMessage() {}
+
+func (x *InputSplit) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InputSplit.ProtoReflect.Descriptor instead.
+func (*InputSplit) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *InputSplit) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *InputSplit) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *InputSplit) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *InputSplit) GetFormat() DataFormat {
+	if x != nil {
+		return x.Format
+	}
+	return DataFormat_TEXT
+}
+
+func (x *InputSplit) GetPosition() int64 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+type AssignSplitResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// values read from the split
+	Values int64 `protobuf:"varint,2,opt,name=values,proto3" json:"values,omitempty"`
+}
+
+func (x *AssignSplitResponse) Reset() {
+	*x = AssignSplitResponse{}
+	mi := &file_proto_mapreduce_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignSplitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignSplitResponse) ProtoMessage() {}
+
+func (x *AssignSplitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignSplitResponse.ProtoReflect.Descriptor instead.
+func (*AssignSplitResponse) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AssignSplitResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *AssignSplitResponse) GetValues() int64 {
+	if x != nil {
+		return x.Values
+	}
+	return 0
+}
+
+type NegotiateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// encodings the mapper can produce, in order of preference
+	Encodings []BatchEncoding `protobuf:"varint,1,rep,packed,name=encodings,proto3,enum=mapreduce.BatchEncoding" json:"encodings,omitempty"`
+}
+
+func (x *NegotiateRequest) Reset() {
+	*x = NegotiateRequest{}
+	mi := &file_proto_mapreduce_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NegotiateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NegotiateRequest) ProtoMessage() {}
+
+func (x *NegotiateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NegotiateRequest.ProtoReflect.Descriptor instead.
+func (*NegotiateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *NegotiateRequest) GetEncodings() []BatchEncoding {
+	if x != nil {
+		return x.Encodings
+	}
+	return nil
+}
+
+type NegotiateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Encoding BatchEncoding `protobuf:"varint,1,opt,name=encoding,proto3,enum=mapreduce.BatchEncoding" json:"encoding,omitempty"`
+}
+
+func (x *NegotiateResponse) Reset() {
+	*x = NegotiateResponse{}
+	mi := &file_proto_mapreduce_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NegotiateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NegotiateResponse) ProtoMessage() {}
+
+func (x *NegotiateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NegotiateResponse.ProtoReflect.Descriptor instead.
+func (*NegotiateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *NegotiateResponse) GetEncoding() BatchEncoding {
+	if x != nil {
+		return x.Encoding
+	}
+	return BatchEncoding_BATCH_RAW
+}
+
+type NotifyMapperDoneRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MapperAddress string `protobuf:"bytes,1,opt,name=mapper_address,json=mapperAddress,proto3" json:"mapper_address,omitempty"`
+}
+
+func (x *NotifyMapperDoneRequest) Reset() {
+	*x = NotifyMapperDoneRequest{}
+	mi := &file_proto_mapreduce_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotifyMapperDoneRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotifyMapperDoneRequest) ProtoMessage() {}
+
+func (x *NotifyMapperDoneRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotifyMapperDoneRequest.ProtoReflect.Descriptor instead.
+func (*NotifyMapperDoneRequest) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *NotifyMapperDoneRequest) GetMapperAddress() string {
+	if x != nil {
+		return x.MapperAddress
+	}
+	return ""
+}
+
+type GetStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetStatusRequest) Reset() {
+	*x = GetStatusRequest{}
+	mi := &file_proto_mapreduce_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusRequest) ProtoMessage() {}
+
+func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{14}
+}
+
+type GetStatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Role  string `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	JobId string `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// Values received from the master (mapper) or from mappers (reducer)
+	ValuesReceived int64 `protobuf:"varint,3,opt,name=values_received,json=valuesReceived,proto3" json:"values_received,omitempty"`
+	// Mappers the reducer is still waiting for
+	MappersPending int32 `protobuf:"varint,4,opt,name=mappers_pending,json=mappersPending,proto3" json:"mappers_pending,omitempty"`
+	// Bytes of output written by the reducer
+	BytesWritten int64 `protobuf:"varint,5,opt,name=bytes_written,json=bytesWritten,proto3" json:"bytes_written,omitempty"`
+	// Values sent to reducers by the mapper
+	ValuesSent int64 `protobuf:"varint,6,opt,name=values_sent,json=valuesSent,proto3" json:"values_sent,omitempty"`
+	// Values written to the output by the reducer
+	ValuesWritten int64 `protobuf:"varint,7,opt,name=values_written,json=valuesWritten,proto3" json:"values_written,omitempty"`
+	// true once the mapper notified every reducer, or the reducer wrote its output
+	Done bool `protobuf:"varint,8,opt,name=done,proto3" json:"done,omitempty"`
+	// Values and bytes sent by the mapper, by reducer address
+	ValuesSentByReducer map[string]int64 `protobuf:"bytes,9,rep,name=values_sent_by_reducer,json=valuesSentByReducer,proto3" json:"values_sent_by_reducer,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	BytesSentByReducer  map[string]int64 `protobuf:"bytes,10,rep,name=bytes_sent_by_reducer,json=bytesSentByReducer,proto3" json:"bytes_sent_by_reducer,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// Output part written by the reducer, set once done
+	Part *PartInfo `protobuf:"bytes,11,opt,name=part,proto3" json:"part,omitempty"`
+	// Duplicate values or records dropped by the mapper or the reducer of a unique job
+	Duplicates int64 `protobuf:"varint,12,opt,name=duplicates,proto3" json:"duplicates,omitempty"`
+	// Why the job failed on this worker, such as a reducer that could not write its output
+	Error string `protobuf:"bytes,13,opt,name=error,proto3" json:"error,omitempty"`
 }
 
-func (x *AssignRoleRequest) Reset() {
-	*x = AssignRoleRequest{}
-	mi := &file_proto_mapreduce_proto_msgTypes[0]
+func (x *GetStatusResponse) Reset() {
+	*x = GetStatusResponse{}
+	mi := &file_proto_mapreduce_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AssignRoleRequest) String() string {
+func (x *GetStatusResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AssignRoleRequest) ProtoMessage() {}
+func (*GetStatusResponse) ProtoMessage() {}
 
-func (x *AssignRoleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_mapreduce_proto_msgTypes[0]
+func (x *GetStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -60,69 +1371,137 @@ func (x *AssignRoleRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AssignRoleRequest.ProtoReflect.Descriptor instead.
-func (*AssignRoleRequest) Descriptor() ([]byte, []int) {
-	return file_proto_mapreduce_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use GetStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *AssignRoleRequest) GetIsMapper() bool {
+func (x *GetStatusResponse) GetRole() string {
 	if x != nil {
-		return x.IsMapper
+		return x.Role
 	}
-	return false
+	return ""
 }
 
-func (x *AssignRoleRequest) GetReducers() []*ReducerInfo {
+func (x *GetStatusResponse) GetJobId() string {
 	if x != nil {
-		return x.Reducers
+		return x.JobId
 	}
-	return nil
+	return ""
 }
 
-func (x *AssignRoleRequest) GetTotalMappers() int32 {
+func (x *GetStatusResponse) GetValuesReceived() int64 {
 	if x != nil {
-		return x.TotalMappers
+		return x.ValuesReceived
 	}
 	return 0
 }
 
-func (x *AssignRoleRequest) GetIntervalStart() int64 {
+func (x *GetStatusResponse) GetMappersPending() int32 {
 	if x != nil {
-		return x.IntervalStart
+		return x.MappersPending
 	}
 	return 0
 }
 
-func (x *AssignRoleRequest) GetIntervalEnd() int64 {
+func (x *GetStatusResponse) GetBytesWritten() int64 {
 	if x != nil {
-		return x.IntervalEnd
+		return x.BytesWritten
 	}
 	return 0
 }
 
-type AssignRoleResponse struct {
+func (x *GetStatusResponse) GetValuesSent() int64 {
+	if x != nil {
+		return x.ValuesSent
+	}
+	return 0
+}
+
+func (x *GetStatusResponse) GetValuesWritten() int64 {
+	if x != nil {
+		return x.ValuesWritten
+	}
+	return 0
+}
+
+func (x *GetStatusResponse) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *GetStatusResponse) GetValuesSentByReducer() map[string]int64 {
+	if x != nil {
+		return x.ValuesSentByReducer
+	}
+	return nil
+}
+
+func (x *GetStatusResponse) GetBytesSentByReducer() map[string]int64 {
+	if x != nil {
+		return x.BytesSentByReducer
+	}
+	return nil
+}
+
+func (x *GetStatusResponse) GetPart() *PartInfo {
+	if x != nil {
+		return x.Part
+	}
+	return nil
+}
+
+func (x *GetStatusResponse) GetDuplicates() int64 {
+	if x != nil {
+		return x.Duplicates
+	}
+	return 0
+}
+
+func (x *GetStatusResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type PartInfo struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Path      string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Partition int32  `protobuf:"varint,2,opt,name=partition,proto3" json:"partition,omitempty"`
+	Records   int64  `protobuf:"varint,3,opt,name=records,proto3" json:"records,omitempty"`
+	Min       int64  `protobuf:"varint,4,opt,name=min,proto3" json:"min,omitempty"`
+	Max       int64  `protobuf:"varint,5,opt,name=max,proto3" json:"max,omitempty"`
+	Bytes     int64  `protobuf:"varint,6,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	// CRC-32C of the file content
+	Crc32C uint32 `protobuf:"varint,7,opt,name=crc32c,proto3" json:"crc32c,omitempty"`
+	// smallest and largest keys of byte key types, instead of min and max
+	MinKey []byte `protobuf:"bytes,8,opt,name=min_key,json=minKey,proto3" json:"min_key,omitempty"`
+	MaxKey []byte `protobuf:"bytes,9,opt,name=max_key,json=maxKey,proto3" json:"max_key,omitempty"`
+	// Histogram jobs: values counted by the part, the sum of its counts
+	Values int64 `protobuf:"varint,10,opt,name=values,proto3" json:"values,omitempty"`
 }
 
-func (x *AssignRoleResponse) Reset() {
-	*x = AssignRoleResponse{}
-	mi := &file_proto_mapreduce_proto_msgTypes[1]
+func (x *PartInfo) Reset() {
+	*x = PartInfo{}
+	mi := &file_proto_mapreduce_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AssignRoleResponse) String() string {
+func (x *PartInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AssignRoleResponse) ProtoMessage() {}
+func (*PartInfo) ProtoMessage() {}
 
-func (x *AssignRoleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_mapreduce_proto_msgTypes[1]
+func (x *PartInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -133,41 +1512,104 @@ func (x *AssignRoleResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AssignRoleResponse.ProtoReflect.Descriptor instead.
-func (*AssignRoleResponse) Descriptor() ([]byte, []int) {
-	return file_proto_mapreduce_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use PartInfo.ProtoReflect.Descriptor instead.
+func (*PartInfo) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *AssignRoleResponse) GetMessage() string {
+func (x *PartInfo) GetPath() string {
 	if x != nil {
-		return x.Message
+		return x.Path
 	}
 	return ""
 }
 
-type SendChunkRequest struct {
+func (x *PartInfo) GetPartition() int32 {
+	if x != nil {
+		return x.Partition
+	}
+	return 0
+}
+
+func (x *PartInfo) GetRecords() int64 {
+	if x != nil {
+		return x.Records
+	}
+	return 0
+}
+
+func (x *PartInfo) GetMin() int64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *PartInfo) GetMax() int64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *PartInfo) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *PartInfo) GetCrc32C() uint32 {
+	if x != nil {
+		return x.Crc32C
+	}
+	return 0
+}
+
+func (x *PartInfo) GetMinKey() []byte {
+	if x != nil {
+		return x.MinKey
+	}
+	return nil
+}
+
+func (x *PartInfo) GetMaxKey() []byte {
+	if x != nil {
+		return x.MaxKey
+	}
+	return nil
+}
+
+func (x *PartInfo) GetValues() int64 {
+	if x != nil {
+		return x.Values
+	}
+	return 0
+}
+
+type FetchOutputRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Values []int64 `protobuf:"varint,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
 }
 
-func (x *SendChunkRequest) Reset() {
-	*x = SendChunkRequest{}
-	mi := &file_proto_mapreduce_proto_msgTypes[2]
+func (x *FetchOutputRequest) Reset() {
+	*x = FetchOutputRequest{}
+	mi := &file_proto_mapreduce_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendChunkRequest) String() string {
+func (x *FetchOutputRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendChunkRequest) ProtoMessage() {}
+func (*FetchOutputRequest) ProtoMessage() {}
 
-func (x *SendChunkRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_mapreduce_proto_msgTypes[2]
+func (x *FetchOutputRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -178,41 +1620,43 @@ func (x *SendChunkRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendChunkRequest.ProtoReflect.Descriptor instead.
-func (*SendChunkRequest) Descriptor() ([]byte, []int) {
-	return file_proto_mapreduce_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use FetchOutputRequest.ProtoReflect.Descriptor instead.
+func (*FetchOutputRequest) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *SendChunkRequest) GetValues() []int64 {
+func (x *FetchOutputRequest) GetJobId() string {
 	if x != nil {
-		return x.Values
+		return x.JobId
 	}
-	return nil
+	return ""
 }
 
-type SendChunkResponse struct {
+type QueryRanksRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	JobId string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// 0-based ranks in the sorted values of the reducer
+	Ranks []int64 `protobuf:"varint,2,rep,packed,name=ranks,proto3" json:"ranks,omitempty"`
 }
 
-func (x *SendChunkResponse) Reset() {
-	*x = SendChunkResponse{}
-	mi := &file_proto_mapreduce_proto_msgTypes[3]
+func (x *QueryRanksRequest) Reset() {
+	*x = QueryRanksRequest{}
+	mi := &file_proto_mapreduce_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendChunkResponse) String() string {
+func (x *QueryRanksRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendChunkResponse) ProtoMessage() {}
+func (*QueryRanksRequest) ProtoMessage() {}
 
-func (x *SendChunkResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_mapreduce_proto_msgTypes[3]
+func (x *QueryRanksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -223,42 +1667,49 @@ func (x *SendChunkResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendChunkResponse.ProtoReflect.Descriptor instead.
-func (*SendChunkResponse) Descriptor() ([]byte, []int) {
-	return file_proto_mapreduce_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use QueryRanksRequest.ProtoReflect.Descriptor instead.
+func (*QueryRanksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *SendChunkResponse) GetMessage() string {
+func (x *QueryRanksRequest) GetJobId() string {
 	if x != nil {
-		return x.Message
+		return x.JobId
 	}
 	return ""
 }
 
-type SendMappedDataRequest struct {
+func (x *QueryRanksRequest) GetRanks() []int64 {
+	if x != nil {
+		return x.Ranks
+	}
+	return nil
+}
+
+type QueryRanksResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Values         []int64 `protobuf:"varint,1,rep,packed,name=values,proto3" json:"values,omitempty"`
-	ReducerAddress string  `protobuf:"bytes,2,opt,name=reducer_address,json=reducerAddress,proto3" json:"reducer_address,omitempty"`
+	// keys of the values at the ranks, in the same order
+	Values []int64 `protobuf:"varint,1,rep,packed,name=values,proto3" json:"values,omitempty"`
 }
 
-func (x *SendMappedDataRequest) Reset() {
-	*x = SendMappedDataRequest{}
-	mi := &file_proto_mapreduce_proto_msgTypes[4]
+func (x *QueryRanksResponse) Reset() {
+	*x = QueryRanksResponse{}
+	mi := &file_proto_mapreduce_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendMappedDataRequest) String() string {
+func (x *QueryRanksResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendMappedDataRequest) ProtoMessage() {}
+func (*QueryRanksResponse) ProtoMessage() {}
 
-func (x *SendMappedDataRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_mapreduce_proto_msgTypes[4]
+func (x *QueryRanksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -269,48 +1720,44 @@ func (x *SendMappedDataRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendMappedDataRequest.ProtoReflect.Descriptor instead.
-func (*SendMappedDataRequest) Descriptor() ([]byte, []int) {
-	return file_proto_mapreduce_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use QueryRanksResponse.ProtoReflect.Descriptor instead.
+func (*QueryRanksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *SendMappedDataRequest) GetValues() []int64 {
+func (x *QueryRanksResponse) GetValues() []int64 {
 	if x != nil {
 		return x.Values
 	}
 	return nil
 }
 
-func (x *SendMappedDataRequest) GetReducerAddress() string {
-	if x != nil {
-		return x.ReducerAddress
-	}
-	return ""
-}
-
-type NotifyMapperDoneRequest struct {
+type OutputBatch struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	MapperAddress string `protobuf:"bytes,1,opt,name=mapper_address,json=mapperAddress,proto3" json:"mapper_address,omitempty"`
+	Values  []int64   `protobuf:"varint,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	Records []*Record `protobuf:"bytes,2,rep,name=records,proto3" json:"records,omitempty"`
+	// Histogram jobs: occurrences of each of the values
+	Counts []int64 `protobuf:"varint,3,rep,packed,name=counts,proto3" json:"counts,omitempty"`
 }
 
-func (x *NotifyMapperDoneRequest) Reset() {
-	*x = NotifyMapperDoneRequest{}
-	mi := &file_proto_mapreduce_proto_msgTypes[5]
+func (x *OutputBatch) Reset() {
+	*x = OutputBatch{}
+	mi := &file_proto_mapreduce_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NotifyMapperDoneRequest) String() string {
+func (x *OutputBatch) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NotifyMapperDoneRequest) ProtoMessage() {}
+func (*OutputBatch) ProtoMessage() {}
 
-func (x *NotifyMapperDoneRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_mapreduce_proto_msgTypes[5]
+func (x *OutputBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_mapreduce_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -321,16 +1768,30 @@ func (x *NotifyMapperDoneRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NotifyMapperDoneRequest.ProtoReflect.Descriptor instead.
-func (*NotifyMapperDoneRequest) Descriptor() ([]byte, []int) {
-	return file_proto_mapreduce_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use OutputBatch.ProtoReflect.Descriptor instead.
+func (*OutputBatch) Descriptor() ([]byte, []int) {
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *NotifyMapperDoneRequest) GetMapperAddress() string {
+func (x *OutputBatch) GetValues() []int64 {
 	if x != nil {
-		return x.MapperAddress
+		return x.Values
 	}
-	return ""
+	return nil
+}
+
+func (x *OutputBatch) GetRecords() []*Record {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *OutputBatch) GetCounts() []int64 {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
 }
 
 type Empty struct {
@@ -341,7 +1802,7 @@ type Empty struct {
 
 func (x *Empty) Reset() {
 	*x = Empty{}
-	mi := &file_proto_mapreduce_proto_msgTypes[6]
+	mi := &file_proto_mapreduce_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -353,7 +1814,7 @@ func (x *Empty) String() string {
 func (*Empty) ProtoMessage() {}
 
 func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_mapreduce_proto_msgTypes[6]
+	mi := &file_proto_mapreduce_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -366,7 +1827,7 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Empty.ProtoReflect.Descriptor instead.
 func (*Empty) Descriptor() ([]byte, []int) {
-	return file_proto_mapreduce_proto_rawDescGZIP(), []int{6}
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{21}
 }
 
 type ReducerInfo struct {
@@ -377,11 +1838,16 @@ type ReducerInfo struct {
 	Address       string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
 	IntervalStart int64  `protobuf:"varint,2,opt,name=interval_start,json=intervalStart,proto3" json:"interval_start,omitempty"`
 	IntervalEnd   int64  `protobuf:"varint,3,opt,name=interval_end,json=intervalEnd,proto3" json:"interval_end,omitempty"`
+	// interval of byte key types. Intervals follow the ascending key order whatever the job's order: the
+	// reducer of the lowest interval, the first one or the last one of a descending job, has no lower
+	// bound, and the reducer of the highest interval no upper bound.
+	KeyStart []byte `protobuf:"bytes,4,opt,name=key_start,json=keyStart,proto3" json:"key_start,omitempty"`
+	KeyEnd   []byte `protobuf:"bytes,5,opt,name=key_end,json=keyEnd,proto3" json:"key_end,omitempty"`
 }
 
 func (x *ReducerInfo) Reset() {
 	*x = ReducerInfo{}
-	mi := &file_proto_mapreduce_proto_msgTypes[7]
+	mi := &file_proto_mapreduce_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -393,7 +1859,7 @@ func (x *ReducerInfo) String() string {
 func (*ReducerInfo) ProtoMessage() {}
 
 func (x *ReducerInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_mapreduce_proto_msgTypes[7]
+	mi := &file_proto_mapreduce_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -406,7 +1872,7 @@ func (x *ReducerInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReducerInfo.ProtoReflect.Descriptor instead.
 func (*ReducerInfo) Descriptor() ([]byte, []int) {
-	return file_proto_mapreduce_proto_rawDescGZIP(), []int{7}
+	return file_proto_mapreduce_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *ReducerInfo) GetAddress() string {
@@ -430,73 +1896,319 @@ func (x *ReducerInfo) GetIntervalEnd() int64 {
 	return 0
 }
 
+func (x *ReducerInfo) GetKeyStart() []byte {
+	if x != nil {
+		return x.KeyStart
+	}
+	return nil
+}
+
+func (x *ReducerInfo) GetKeyEnd() []byte {
+	if x != nil {
+		return x.KeyEnd
+	}
+	return nil
+}
+
 var File_proto_mapreduce_proto protoreflect.FileDescriptor
 
 var file_proto_mapreduce_proto_rawDesc = []byte{
 	0x0a, 0x15, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63,
 	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75,
-	0x63, 0x65, 0x22, 0xd3, 0x01, 0x0a, 0x11, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x52, 0x6f, 0x6c,
-	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x73, 0x5f, 0x6d,
-	0x61, 0x70, 0x70, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x4d,
-	0x61, 0x70, 0x70, 0x65, 0x72, 0x12, 0x32, 0x0a, 0x08, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x72,
-	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64,
-	0x75, 0x63, 0x65, 0x2e, 0x52, 0x65, 0x64, 0x75, 0x63, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52,
-	0x08, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x72, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x6f, 0x74,
-	0x61, 0x6c, 0x5f, 0x6d, 0x61, 0x70, 0x70, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4d, 0x61, 0x70, 0x70, 0x65, 0x72, 0x73, 0x12, 0x25,
-	0x0a, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c,
-	0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61,
-	0x6c, 0x5f, 0x65, 0x6e, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x69, 0x6e, 0x74,
-	0x65, 0x72, 0x76, 0x61, 0x6c, 0x45, 0x6e, 0x64, 0x22, 0x2e, 0x0a, 0x12, 0x41, 0x73, 0x73, 0x69,
+	0x63, 0x65, 0x22, 0x67, 0x0a, 0x07, 0x53, 0x6f, 0x72, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x66, 0x69,
+	0x65, 0x6c, 0x64, 0x12, 0x26, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x4b, 0x65,
+	0x79, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x64,
+	0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0a, 0x64, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x9a, 0x06, 0x0a, 0x11,
+	0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x73, 0x5f, 0x6d, 0x61, 0x70, 0x70, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x4d, 0x61, 0x70, 0x70, 0x65, 0x72, 0x12, 0x32,
+	0x0a, 0x08, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x52, 0x65, 0x64,
+	0x75, 0x63, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65,
+	0x72, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x6d, 0x61, 0x70, 0x70,
+	0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x4d, 0x61, 0x70, 0x70, 0x65, 0x72, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x76, 0x61, 0x6c, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x21,
+	0x0a, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x65, 0x6e, 0x64, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x45, 0x6e,
+	0x64, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x61, 0x70, 0x70, 0x65, 0x72, 0x5f, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6d, 0x61, 0x70, 0x70, 0x65, 0x72, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x70,
+	0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09,
+	0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x44, 0x69, 0x72, 0x12, 0x36, 0x0a, 0x0b, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e,
+	0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x4d, 0x6f, 0x64, 0x65, 0x52, 0x0a, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x4d, 0x6f, 0x64, 0x65,
+	0x12, 0x3a, 0x0a, 0x0d, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x61,
+	0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64,
+	0x75, 0x63, 0x65, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x52, 0x0c,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x29, 0x0a, 0x10,
+	0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x73, 0x68, 0x75, 0x66, 0x66, 0x6c, 0x65,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x53, 0x68, 0x75, 0x66, 0x66, 0x6c, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x6d, 0x70, 0x72,
+	0x65, 0x73, 0x73, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0e, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x12, 0x3f, 0x0a, 0x0e, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69,
+	0x6e, 0x67, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65,
+	0x64, 0x75, 0x63, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69,
+	0x6e, 0x67, 0x52, 0x0d, 0x62, 0x61, 0x74, 0x63, 0x68, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e,
+	0x67, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x2d, 0x0a, 0x08, 0x6b,
+	0x65, 0x79, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e,
+	0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x4b, 0x65, 0x79, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x07, 0x6b, 0x65, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f,
+	0x6d, 0x70, 0x61, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x63, 0x6f, 0x6d, 0x70, 0x61, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x65,
+	0x73, 0x63, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x12, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
+	0x64, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x6e,
+	0x69, 0x71, 0x75, 0x65, 0x18, 0x13, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x75, 0x6e, 0x69, 0x71,
+	0x75, 0x65, 0x12, 0x24, 0x0a, 0x03, 0x6a, 0x6f, 0x62, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x12, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x4a, 0x6f, 0x62, 0x4b,
+	0x69, 0x6e, 0x64, 0x52, 0x03, 0x6a, 0x6f, 0x62, 0x22, 0x2e, 0x0a, 0x12, 0x41, 0x73, 0x73, 0x69,
 	0x67, 0x6e, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18,
 	0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x2a, 0x0a, 0x10, 0x53, 0x65, 0x6e, 0x64,
+	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x57, 0x0a, 0x10, 0x53, 0x65, 0x6e, 0x64,
 	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06,
 	0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x03, 0x52, 0x06, 0x76, 0x61,
-	0x6c, 0x75, 0x65, 0x73, 0x22, 0x2d, 0x0a, 0x11, 0x53, 0x65, 0x6e, 0x64, 0x43, 0x68, 0x75, 0x6e,
-	0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x22, 0x58, 0x0a, 0x15, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x61, 0x70, 0x70, 0x65,
-	0x64, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x03, 0x52, 0x06, 0x76, 0x61,
-	0x6c, 0x75, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x72, 0x5f,
-	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x72,
-	0x65, 0x64, 0x75, 0x63, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0x40, 0x0a,
-	0x17, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x4d, 0x61, 0x70, 0x70, 0x65, 0x72, 0x44, 0x6f, 0x6e,
-	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x6d, 0x61, 0x70, 0x70,
-	0x65, 0x72, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0d, 0x6d, 0x61, 0x70, 0x70, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22,
-	0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x71, 0x0a, 0x0b, 0x52, 0x65, 0x64, 0x75,
-	0x63, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65,
-	0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
-	0x73, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x73, 0x74,
-	0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72,
-	0x76, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x74, 0x65,
-	0x72, 0x76, 0x61, 0x6c, 0x5f, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b,
-	0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x45, 0x6e, 0x64, 0x32, 0xb2, 0x02, 0x0a, 0x0d,
-	0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x49, 0x0a,
-	0x0a, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x1c, 0x2e, 0x6d, 0x61,
-	0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x52, 0x6f,
-	0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x6d, 0x61, 0x70, 0x72,
-	0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x52, 0x6f, 0x6c, 0x65,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x09, 0x53, 0x65, 0x6e, 0x64,
-	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x1b, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63,
-	0x65, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x53,
-	0x65, 0x6e, 0x64, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x44, 0x0a, 0x0e, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x61, 0x70, 0x70, 0x65, 0x64, 0x44, 0x61,
-	0x74, 0x61, 0x12, 0x20, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x53,
-	0x65, 0x6e, 0x64, 0x4d, 0x61, 0x70, 0x70, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71,
+	0x6c, 0x75, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63,
+	0x65, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x73, 0x22, 0x63, 0x0a, 0x06, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x18, 0x0a,
+	0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07,
+	0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x6b, 0x65, 0x79, 0x5f, 0x62,
+	0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x42,
+	0x79, 0x74, 0x65, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x03, 0x73, 0x65, 0x71, 0x22, 0x2d, 0x0a, 0x11, 0x53, 0x65, 0x6e, 0x64, 0x43, 0x68,
+	0x75, 0x6e, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xd0, 0x01, 0x0a, 0x15, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x61,
+	0x70, 0x70, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x03, 0x52,
+	0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x72, 0x65, 0x64, 0x75, 0x63,
+	0x65, 0x72, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0e, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x12, 0x31, 0x0a, 0x07, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x45, 0x6e,
+	0x63, 0x6f, 0x64, 0x65, 0x64, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x07, 0x65, 0x6e, 0x63, 0x6f,
+	0x64, 0x65, 0x64, 0x12, 0x2b, 0x0a, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65,
+	0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73,
+	0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x03,
+	0x52, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x22, 0x6e, 0x0a, 0x0c, 0x45, 0x6e, 0x63, 0x6f,
+	0x64, 0x65, 0x64, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x34, 0x0a, 0x08, 0x65, 0x6e, 0x63, 0x6f,
+	0x64, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x6d, 0x61, 0x70,
+	0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x45, 0x6e, 0x63, 0x6f,
+	0x64, 0x69, 0x6e, 0x67, 0x52, 0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x14,
+	0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0xde, 0x01, 0x0a, 0x12, 0x41, 0x73, 0x73,
+	0x69, 0x67, 0x6e, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x2d, 0x0a, 0x06, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x49, 0x6e, 0x70, 0x75,
+	0x74, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x52, 0x06, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x73, 0x12, 0x1b,
+	0x0a, 0x09, 0x6b, 0x65, 0x79, 0x5f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x64,
+	0x65, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x64, 0x65, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x65, 0x72, 0x12, 0x2d, 0x0a, 0x08, 0x6b, 0x65, 0x79,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6d, 0x61,
+	0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x4b, 0x65, 0x79, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x07, 0x6b, 0x65, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2f, 0x0a, 0x09, 0x73, 0x6f, 0x72, 0x74,
+	0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x61,
+	0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x53, 0x6f, 0x72, 0x74, 0x4b, 0x65, 0x79, 0x52,
+	0x08, 0x73, 0x6f, 0x72, 0x74, 0x4b, 0x65, 0x79, 0x73, 0x22, 0x9b, 0x01, 0x0a, 0x0a, 0x49, 0x6e,
+	0x70, 0x75, 0x74, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06,
+	0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66,
+	0x66, 0x73, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x2d, 0x0a, 0x06,
+	0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x6d,
+	0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x46, 0x6f, 0x72,
+	0x6d, 0x61, 0x74, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x47, 0x0a, 0x13, 0x41, 0x73, 0x73, 0x69, 0x67,
+	0x6e, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73,
+	0x22, 0x4a, 0x0a, 0x10, 0x4e, 0x65, 0x67, 0x6f, 0x74, 0x69, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x36, 0x0a, 0x09, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64,
+	0x75, 0x63, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e,
+	0x67, 0x52, 0x09, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x49, 0x0a, 0x11,
+	0x4e, 0x65, 0x67, 0x6f, 0x74, 0x69, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x34, 0x0a, 0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x08, 0x65,
+	0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x40, 0x0a, 0x17, 0x4e, 0x6f, 0x74, 0x69, 0x66,
+	0x79, 0x4d, 0x61, 0x70, 0x70, 0x65, 0x72, 0x44, 0x6f, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x6d, 0x61, 0x70, 0x70, 0x65, 0x72, 0x5f, 0x61, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6d, 0x61, 0x70, 0x70,
+	0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0x12, 0x0a, 0x10, 0x47, 0x65, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xd4, 0x05,
+	0x0a, 0x11, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64, 0x12, 0x27,
+	0x0a, 0x0f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x52,
+	0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x6d, 0x61, 0x70, 0x70, 0x65,
+	0x72, 0x73, 0x5f, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0e, 0x6d, 0x61, 0x70, 0x70, 0x65, 0x72, 0x73, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67,
+	0x12, 0x23, 0x0a, 0x0d, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x77, 0x72, 0x69, 0x74, 0x74, 0x65,
+	0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x62, 0x79, 0x74, 0x65, 0x73, 0x57, 0x72,
+	0x69, 0x74, 0x74, 0x65, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x5f,
+	0x73, 0x65, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x73, 0x53, 0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73,
+	0x5f, 0x77, 0x72, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x57, 0x72, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x12, 0x12, 0x0a,
+	0x04, 0x64, 0x6f, 0x6e, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x64, 0x6f, 0x6e,
+	0x65, 0x12, 0x6a, 0x0a, 0x16, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x5f, 0x73, 0x65, 0x6e, 0x74,
+	0x5f, 0x62, 0x79, 0x5f, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x72, 0x18, 0x09, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x35, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x47, 0x65,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x53, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x52, 0x65, 0x64, 0x75,
+	0x63, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x13, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73,
+	0x53, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x52, 0x65, 0x64, 0x75, 0x63, 0x65, 0x72, 0x12, 0x67, 0x0a,
+	0x15, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x73, 0x65, 0x6e, 0x74, 0x5f, 0x62, 0x79, 0x5f, 0x72,
+	0x65, 0x64, 0x75, 0x63, 0x65, 0x72, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x6d,
+	0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x53, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x52, 0x65, 0x64, 0x75, 0x63, 0x65, 0x72, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x12, 0x62, 0x79, 0x74, 0x65, 0x73, 0x53, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x52,
+	0x65, 0x64, 0x75, 0x63, 0x65, 0x72, 0x12, 0x27, 0x0a, 0x04, 0x70, 0x61, 0x72, 0x74, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65,
+	0x2e, 0x50, 0x61, 0x72, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x70, 0x61, 0x72, 0x74, 0x12,
+	0x1e, 0x0a, 0x0a, 0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0a, 0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x1a, 0x46, 0x0a, 0x18, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x53,
+	0x65, 0x6e, 0x74, 0x42, 0x79, 0x52, 0x65, 0x64, 0x75, 0x63, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x45, 0x0a,
+	0x17, 0x42, 0x79, 0x74, 0x65, 0x73, 0x53, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x52, 0x65, 0x64, 0x75,
+	0x63, 0x65, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x22, 0xf2, 0x01, 0x0a, 0x08, 0x50, 0x61, 0x72, 0x74, 0x49, 0x6e, 0x66,
+	0x6f, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x10, 0x0a,
+	0x03, 0x6d, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6d, 0x69, 0x6e, 0x12,
+	0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6d, 0x61,
+	0x78, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x72, 0x63, 0x33, 0x32,
+	0x63, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63, 0x72, 0x63, 0x33, 0x32, 0x63, 0x12,
+	0x17, 0x0a, 0x07, 0x6d, 0x69, 0x6e, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x06, 0x6d, 0x69, 0x6e, 0x4b, 0x65, 0x79, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x61, 0x78, 0x5f,
+	0x6b, 0x65, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6d, 0x61, 0x78, 0x4b, 0x65,
+	0x79, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x2b, 0x0a, 0x12, 0x46, 0x65, 0x74,
+	0x63, 0x68, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64, 0x22, 0x40, 0x0a, 0x11, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52,
+	0x61, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x6a,
+	0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x6f, 0x62,
+	0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x61, 0x6e, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x03, 0x52, 0x05, 0x72, 0x61, 0x6e, 0x6b, 0x73, 0x22, 0x2c, 0x0a, 0x12, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x52, 0x61, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x03, 0x52, 0x06,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x6a, 0x0a, 0x0b, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x03, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x2b, 0x0a,
+	0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x52, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x03, 0x52, 0x06, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x73, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0xa7, 0x01, 0x0a, 0x0b,
+	0x52, 0x65, 0x64, 0x75, 0x63, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x61,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61,
+	0x6c, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x21, 0x0a, 0x0c,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x45, 0x6e, 0x64, 0x12,
+	0x1b, 0x0a, 0x09, 0x6b, 0x65, 0x79, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x17, 0x0a, 0x07,
+	0x6b, 0x65, 0x79, 0x5f, 0x65, 0x6e, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6b,
+	0x65, 0x79, 0x45, 0x6e, 0x64, 0x2a, 0x31, 0x0a, 0x0a, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x4d,
+	0x6f, 0x64, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x4f, 0x55, 0x54, 0x50, 0x55, 0x54, 0x5f, 0x50, 0x41,
+	0x52, 0x54, 0x53, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x4f, 0x55, 0x54, 0x50, 0x55, 0x54, 0x5f,
+	0x4d, 0x45, 0x52, 0x47, 0x45, 0x44, 0x10, 0x01, 0x2a, 0x34, 0x0a, 0x0a, 0x44, 0x61, 0x74, 0x61,
+	0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x45, 0x58, 0x54, 0x10, 0x00,
+	0x12, 0x0d, 0x0a, 0x09, 0x42, 0x49, 0x4e, 0x41, 0x52, 0x59, 0x5f, 0x4c, 0x45, 0x10, 0x01, 0x12,
+	0x0d, 0x0a, 0x09, 0x42, 0x49, 0x4e, 0x41, 0x52, 0x59, 0x5f, 0x42, 0x45, 0x10, 0x02, 0x2a, 0x54,
+	0x0a, 0x0d, 0x42, 0x61, 0x74, 0x63, 0x68, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x12,
+	0x0d, 0x0a, 0x09, 0x42, 0x41, 0x54, 0x43, 0x48, 0x5f, 0x52, 0x41, 0x57, 0x10, 0x00, 0x12, 0x16,
+	0x0a, 0x12, 0x42, 0x41, 0x54, 0x43, 0x48, 0x5f, 0x44, 0x45, 0x4c, 0x54, 0x41, 0x5f, 0x56, 0x41,
+	0x52, 0x49, 0x4e, 0x54, 0x10, 0x01, 0x12, 0x1c, 0x0a, 0x18, 0x42, 0x41, 0x54, 0x43, 0x48, 0x5f,
+	0x44, 0x45, 0x4c, 0x54, 0x41, 0x5f, 0x56, 0x41, 0x52, 0x49, 0x4e, 0x54, 0x5f, 0x46, 0x4c, 0x41,
+	0x54, 0x45, 0x10, 0x02, 0x2a, 0x3d, 0x0a, 0x07, 0x4a, 0x6f, 0x62, 0x4b, 0x69, 0x6e, 0x64, 0x12,
+	0x0c, 0x0a, 0x08, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x4f, 0x52, 0x54, 0x10, 0x00, 0x12, 0x11, 0x0a,
+	0x0d, 0x4a, 0x4f, 0x42, 0x5f, 0x48, 0x49, 0x53, 0x54, 0x4f, 0x47, 0x52, 0x41, 0x4d, 0x10, 0x01,
+	0x12, 0x11, 0x0a, 0x0d, 0x4a, 0x4f, 0x42, 0x5f, 0x51, 0x55, 0x41, 0x4e, 0x54, 0x49, 0x4c, 0x45,
+	0x53, 0x10, 0x02, 0x2a, 0x6c, 0x0a, 0x07, 0x4b, 0x65, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0d,
+	0x0a, 0x09, 0x4b, 0x45, 0x59, 0x5f, 0x49, 0x4e, 0x54, 0x36, 0x34, 0x10, 0x00, 0x12, 0x0e, 0x0a,
+	0x0a, 0x4b, 0x45, 0x59, 0x5f, 0x53, 0x54, 0x52, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x0e, 0x0a,
+	0x0a, 0x4b, 0x45, 0x59, 0x5f, 0x55, 0x49, 0x4e, 0x54, 0x36, 0x34, 0x10, 0x02, 0x12, 0x0f, 0x0a,
+	0x0b, 0x4b, 0x45, 0x59, 0x5f, 0x46, 0x4c, 0x4f, 0x41, 0x54, 0x36, 0x34, 0x10, 0x03, 0x12, 0x0e,
+	0x0a, 0x0a, 0x4b, 0x45, 0x59, 0x5f, 0x42, 0x49, 0x47, 0x49, 0x4e, 0x54, 0x10, 0x04, 0x12, 0x11,
+	0x0a, 0x0d, 0x4b, 0x45, 0x59, 0x5f, 0x43, 0x4f, 0x4d, 0x50, 0x4f, 0x53, 0x49, 0x54, 0x45, 0x10,
+	0x05, 0x32, 0xa3, 0x05, 0x0a, 0x0d, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x49, 0x0a, 0x0a, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x52, 0x6f, 0x6c,
+	0x65, 0x12, 0x1c, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x41, 0x73,
+	0x73, 0x69, 0x67, 0x6e, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1d, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x41, 0x73, 0x73, 0x69,
+	0x67, 0x6e, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46,
+	0x0a, 0x09, 0x53, 0x65, 0x6e, 0x64, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x1b, 0x2e, 0x6d, 0x61,
+	0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x43, 0x68, 0x75, 0x6e,
+	0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65,
+	0x64, 0x75, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x0e, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x61,
+	0x70, 0x70, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x12, 0x20, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65,
+	0x64, 0x75, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x4d, 0x61, 0x70, 0x70, 0x65, 0x64, 0x44,
+	0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x6d, 0x61, 0x70,
+	0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x48, 0x0a, 0x10,
+	0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x4d, 0x61, 0x70, 0x70, 0x65, 0x72, 0x44, 0x6f, 0x6e, 0x65,
+	0x12, 0x22, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x4e, 0x6f, 0x74,
+	0x69, 0x66, 0x79, 0x4d, 0x61, 0x70, 0x70, 0x65, 0x72, 0x44, 0x6f, 0x6e, 0x65, 0x52, 0x65, 0x71,
 	0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65,
-	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x48, 0x0a, 0x10, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79,
-	0x4d, 0x61, 0x70, 0x70, 0x65, 0x72, 0x44, 0x6f, 0x6e, 0x65, 0x12, 0x22, 0x2e, 0x6d, 0x61, 0x70,
-	0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x4d, 0x61, 0x70,
-	0x70, 0x65, 0x72, 0x44, 0x6f, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10,
-	0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
-	0x42, 0x1b, 0x5a, 0x19, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2f, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x3b, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x46, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x1b, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e,
+	0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1c, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46,
+	0x0a, 0x0b, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x1d, 0x2e,
+	0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x6d,
+	0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x30, 0x01, 0x12, 0x46, 0x0a, 0x09, 0x4e, 0x65, 0x67, 0x6f, 0x74, 0x69,
+	0x61, 0x74, 0x65, 0x12, 0x1b, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e,
+	0x4e, 0x65, 0x67, 0x6f, 0x74, 0x69, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1c, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x4e, 0x65, 0x67,
+	0x6f, 0x74, 0x69, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4c,
+	0x0a, 0x0b, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x12, 0x1d, 0x2e,
+	0x6d, 0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e,
+	0x53, 0x70, 0x6c, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x6d,
+	0x61, 0x70, 0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x53,
+	0x70, 0x6c, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a, 0x0a,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x6b, 0x73, 0x12, 0x1c, 0x2e, 0x6d, 0x61, 0x70,
+	0x72, 0x65, 0x64, 0x75, 0x63, 0x65, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x6b,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x6d, 0x61, 0x70, 0x72, 0x65,
+	0x64, 0x75, 0x63, 0x65, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x61, 0x6e, 0x6b, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x1b, 0x5a, 0x19, 0x6d, 0x61, 0x70, 0x72, 0x65,
+	0x64, 0x75, 0x63, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x3b, 0x6d, 0x61, 0x70, 0x72, 0x65,
+	0x64, 0x75, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -511,32 +2223,85 @@ func file_proto_mapreduce_proto_rawDescGZIP() []byte {
 	return file_proto_mapreduce_proto_rawDescData
 }
 
-var file_proto_mapreduce_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_mapreduce_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_proto_mapreduce_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
 var file_proto_mapreduce_proto_goTypes = []any{
-	(*AssignRoleRequest)(nil),       // 0: mapreduce.AssignRoleRequest
-	(*AssignRoleResponse)(nil),      // 1: mapreduce.AssignRoleResponse
-	(*SendChunkRequest)(nil),        // 2: mapreduce.SendChunkRequest
-	(*SendChunkResponse)(nil),       // 3: mapreduce.SendChunkResponse
-	(*SendMappedDataRequest)(nil),   // 4: mapreduce.SendMappedDataRequest
-	(*NotifyMapperDoneRequest)(nil), // 5: mapreduce.NotifyMapperDoneRequest
-	(*Empty)(nil),                   // 6: mapreduce.Empty
-	(*ReducerInfo)(nil),             // 7: mapreduce.ReducerInfo
+	(OutputMode)(0),                 // 0: mapreduce.OutputMode
+	(DataFormat)(0),                 // 1: mapreduce.DataFormat
+	(BatchEncoding)(0),              // 2: mapreduce.BatchEncoding
+	(JobKind)(0),                    // 3: mapreduce.JobKind
+	(KeyType)(0),                    // 4: mapreduce.KeyType
+	(*SortKey)(nil),                 // 5: mapreduce.SortKey
+	(*AssignRoleRequest)(nil),       // 6: mapreduce.AssignRoleRequest
+	(*AssignRoleResponse)(nil),      // 7: mapreduce.AssignRoleResponse
+	(*SendChunkRequest)(nil),        // 8: mapreduce.SendChunkRequest
+	(*Record)(nil),                  // 9: mapreduce.Record
+	(*SendChunkResponse)(nil),       // 10: mapreduce.SendChunkResponse
+	(*SendMappedDataRequest)(nil),   // 11: mapreduce.SendMappedDataRequest
+	(*EncodedBatch)(nil),            // 12: mapreduce.EncodedBatch
+	(*AssignSplitRequest)(nil),      // 13: mapreduce.AssignSplitRequest
+	(*InputSplit)(nil),              // 14: mapreduce.InputSplit
+	(*AssignSplitResponse)(nil),     // 15: mapreduce.AssignSplitResponse
+	(*NegotiateRequest)(nil),        // 16: mapreduce.NegotiateRequest
+	(*NegotiateResponse)(nil),       // 17: mapreduce.NegotiateResponse
+	(*NotifyMapperDoneRequest)(nil), // 18: mapreduce.NotifyMapperDoneRequest
+	(*GetStatusRequest)(nil),        // 19: mapreduce.GetStatusRequest
+	(*GetStatusResponse)(nil),       // 20: mapreduce.GetStatusResponse
+	(*PartInfo)(nil),                // 21: mapreduce.PartInfo
+	(*FetchOutputRequest)(nil),      // 22: mapreduce.FetchOutputRequest
+	(*QueryRanksRequest)(nil),       // 23: mapreduce.QueryRanksRequest
+	(*QueryRanksResponse)(nil),      // 24: mapreduce.QueryRanksResponse
+	(*OutputBatch)(nil),             // 25: mapreduce.OutputBatch
+	(*Empty)(nil),                   // 26: mapreduce.Empty
+	(*ReducerInfo)(nil),             // 27: mapreduce.ReducerInfo
+	nil,                             // 28: mapreduce.GetStatusResponse.ValuesSentByReducerEntry
+	nil,                             // 29: mapreduce.GetStatusResponse.BytesSentByReducerEntry
 }
 var file_proto_mapreduce_proto_depIdxs = []int32{
-	7, // 0: mapreduce.AssignRoleRequest.reducers:type_name -> mapreduce.ReducerInfo
-	0, // 1: mapreduce.WorkerService.AssignRole:input_type -> mapreduce.AssignRoleRequest
-	2, // 2: mapreduce.WorkerService.SendChunk:input_type -> mapreduce.SendChunkRequest
-	4, // 3: mapreduce.WorkerService.SendMappedData:input_type -> mapreduce.SendMappedDataRequest
-	5, // 4: mapreduce.WorkerService.NotifyMapperDone:input_type -> mapreduce.NotifyMapperDoneRequest
-	1, // 5: mapreduce.WorkerService.AssignRole:output_type -> mapreduce.AssignRoleResponse
-	3, // 6: mapreduce.WorkerService.SendChunk:output_type -> mapreduce.SendChunkResponse
-	6, // 7: mapreduce.WorkerService.SendMappedData:output_type -> mapreduce.Empty
-	6, // 8: mapreduce.WorkerService.NotifyMapperDone:output_type -> mapreduce.Empty
-	5, // [5:9] is the sub-list for method output_type
-	1, // [1:5] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	4,  // 0: mapreduce.SortKey.type:type_name -> mapreduce.KeyType
+	27, // 1: mapreduce.AssignRoleRequest.reducers:type_name -> mapreduce.ReducerInfo
+	0,  // 2: mapreduce.AssignRoleRequest.output_mode:type_name -> mapreduce.OutputMode
+	1,  // 3: mapreduce.AssignRoleRequest.output_format:type_name -> mapreduce.DataFormat
+	2,  // 4: mapreduce.AssignRoleRequest.batch_encoding:type_name -> mapreduce.BatchEncoding
+	4,  // 5: mapreduce.AssignRoleRequest.key_type:type_name -> mapreduce.KeyType
+	3,  // 6: mapreduce.AssignRoleRequest.job:type_name -> mapreduce.JobKind
+	9,  // 7: mapreduce.SendChunkRequest.records:type_name -> mapreduce.Record
+	12, // 8: mapreduce.SendMappedDataRequest.encoded:type_name -> mapreduce.EncodedBatch
+	9,  // 9: mapreduce.SendMappedDataRequest.records:type_name -> mapreduce.Record
+	2,  // 10: mapreduce.EncodedBatch.encoding:type_name -> mapreduce.BatchEncoding
+	14, // 11: mapreduce.AssignSplitRequest.splits:type_name -> mapreduce.InputSplit
+	4,  // 12: mapreduce.AssignSplitRequest.key_type:type_name -> mapreduce.KeyType
+	5,  // 13: mapreduce.AssignSplitRequest.sort_keys:type_name -> mapreduce.SortKey
+	1,  // 14: mapreduce.InputSplit.format:type_name -> mapreduce.DataFormat
+	2,  // 15: mapreduce.NegotiateRequest.encodings:type_name -> mapreduce.BatchEncoding
+	2,  // 16: mapreduce.NegotiateResponse.encoding:type_name -> mapreduce.BatchEncoding
+	28, // 17: mapreduce.GetStatusResponse.values_sent_by_reducer:type_name -> mapreduce.GetStatusResponse.ValuesSentByReducerEntry
+	29, // 18: mapreduce.GetStatusResponse.bytes_sent_by_reducer:type_name -> mapreduce.GetStatusResponse.BytesSentByReducerEntry
+	21, // 19: mapreduce.GetStatusResponse.part:type_name -> mapreduce.PartInfo
+	9,  // 20: mapreduce.OutputBatch.records:type_name -> mapreduce.Record
+	6,  // 21: mapreduce.WorkerService.AssignRole:input_type -> mapreduce.AssignRoleRequest
+	8,  // 22: mapreduce.WorkerService.SendChunk:input_type -> mapreduce.SendChunkRequest
+	11, // 23: mapreduce.WorkerService.SendMappedData:input_type -> mapreduce.SendMappedDataRequest
+	18, // 24: mapreduce.WorkerService.NotifyMapperDone:input_type -> mapreduce.NotifyMapperDoneRequest
+	19, // 25: mapreduce.WorkerService.GetStatus:input_type -> mapreduce.GetStatusRequest
+	22, // 26: mapreduce.WorkerService.FetchOutput:input_type -> mapreduce.FetchOutputRequest
+	16, // 27: mapreduce.WorkerService.Negotiate:input_type -> mapreduce.NegotiateRequest
+	13, // 28: mapreduce.WorkerService.AssignSplit:input_type -> mapreduce.AssignSplitRequest
+	23, // 29: mapreduce.WorkerService.QueryRanks:input_type -> mapreduce.QueryRanksRequest
+	7,  // 30: mapreduce.WorkerService.AssignRole:output_type -> mapreduce.AssignRoleResponse
+	10, // 31: mapreduce.WorkerService.SendChunk:output_type -> mapreduce.SendChunkResponse
+	26, // 32: mapreduce.WorkerService.SendMappedData:output_type -> mapreduce.Empty
+	26, // 33: mapreduce.WorkerService.NotifyMapperDone:output_type -> mapreduce.Empty
+	20, // 34: mapreduce.WorkerService.GetStatus:output_type -> mapreduce.GetStatusResponse
+	25, // 35: mapreduce.WorkerService.FetchOutput:output_type -> mapreduce.OutputBatch
+	17, // 36: mapreduce.WorkerService.Negotiate:output_type -> mapreduce.NegotiateResponse
+	15, // 37: mapreduce.WorkerService.AssignSplit:output_type -> mapreduce.AssignSplitResponse
+	24, // 38: mapreduce.WorkerService.QueryRanks:output_type -> mapreduce.QueryRanksResponse
+	30, // [30:39] is the sub-list for method output_type
+	21, // [21:30] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_proto_mapreduce_proto_init() }
@@ -549,13 +2314,14 @@ func file_proto_mapreduce_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_mapreduce_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   8,
+			NumEnums:      5,
+			NumMessages:   25,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_mapreduce_proto_goTypes,
 		DependencyIndexes: file_proto_mapreduce_proto_depIdxs,
+		EnumInfos:         file_proto_mapreduce_proto_enumTypes,
 		MessageInfos:      file_proto_mapreduce_proto_msgTypes,
 	}.Build()
 	File_proto_mapreduce_proto = out.File
@@ -23,6 +23,11 @@ const (
 	WorkerService_SendChunk_FullMethodName        = "/mapreduce.WorkerService/SendChunk"
 	WorkerService_SendMappedData_FullMethodName   = "/mapreduce.WorkerService/SendMappedData"
 	WorkerService_NotifyMapperDone_FullMethodName = "/mapreduce.WorkerService/NotifyMapperDone"
+	WorkerService_GetStatus_FullMethodName        = "/mapreduce.WorkerService/GetStatus"
+	WorkerService_FetchOutput_FullMethodName      = "/mapreduce.WorkerService/FetchOutput"
+	WorkerService_Negotiate_FullMethodName        = "/mapreduce.WorkerService/Negotiate"
+	WorkerService_AssignSplit_FullMethodName      = "/mapreduce.WorkerService/AssignSplit"
+	WorkerService_QueryRanks_FullMethodName       = "/mapreduce.WorkerService/QueryRanks"
 )
 
 // WorkerServiceClient is the client API for WorkerService service.
@@ -36,6 +41,16 @@ type WorkerServiceClient interface {
 	SendMappedData(ctx context.Context, in *SendMappedDataRequest, opts ...grpc.CallOption) (*Empty, error)
 	// Mapper -> Reducer: notify that the mapper finished sending data
 	NotifyMapperDone(ctx context.Context, in *NotifyMapperDoneRequest, opts ...grpc.CallOption) (*Empty, error)
+	// Master -> Worker: reports the progress of the current job
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
+	// Master -> Reducer: streams the sorted output retained in merged output mode
+	FetchOutput(ctx context.Context, in *FetchOutputRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OutputBatch], error)
+	// Mapper -> Reducer: agrees on the encoding of the batches sent on this connection
+	Negotiate(ctx context.Context, in *NegotiateRequest, opts ...grpc.CallOption) (*NegotiateResponse, error)
+	// Master -> Mapper: reads byte ranges of the input from a shared path, instead of receiving a chunk
+	AssignSplit(ctx context.Context, in *AssignSplitRequest, opts ...grpc.CallOption) (*AssignSplitResponse, error)
+	// Master -> Reducer: returns the values at ranks of the sorted values retained by a quantiles job
+	QueryRanks(ctx context.Context, in *QueryRanksRequest, opts ...grpc.CallOption) (*QueryRanksResponse, error)
 }
 
 type workerServiceClient struct {
@@ -86,6 +101,65 @@ func (c *workerServiceClient) NotifyMapperDone(ctx context.Context, in *NotifyMa
 	return out, nil
 }
 
+func (c *workerServiceClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStatusResponse)
+	err := c.cc.Invoke(ctx, WorkerService_GetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerServiceClient) FetchOutput(ctx context.Context, in *FetchOutputRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OutputBatch], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WorkerService_ServiceDesc.Streams[0], WorkerService_FetchOutput_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FetchOutputRequest, OutputBatch]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WorkerService_FetchOutputClient = grpc.ServerStreamingClient[OutputBatch]
+
+func (c *workerServiceClient) Negotiate(ctx context.Context, in *NegotiateRequest, opts ...grpc.CallOption) (*NegotiateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NegotiateResponse)
+	err := c.cc.Invoke(ctx, WorkerService_Negotiate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerServiceClient) AssignSplit(ctx context.Context, in *AssignSplitRequest, opts ...grpc.CallOption) (*AssignSplitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssignSplitResponse)
+	err := c.cc.Invoke(ctx, WorkerService_AssignSplit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerServiceClient) QueryRanks(ctx context.Context, in *QueryRanksRequest, opts ...grpc.CallOption) (*QueryRanksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryRanksResponse)
+	err := c.cc.Invoke(ctx, WorkerService_QueryRanks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WorkerServiceServer is the server API for WorkerService service.
 // All implementations must embed UnimplementedWorkerServiceServer
 // for forward compatibility.
@@ -97,6 +171,16 @@ type WorkerServiceServer interface {
 	SendMappedData(context.Context, *SendMappedDataRequest) (*Empty, error)
 	// Mapper -> Reducer: notify that the mapper finished sending data
 	NotifyMapperDone(context.Context, *NotifyMapperDoneRequest) (*Empty, error)
+	// Master -> Worker: reports the progress of the current job
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+	// Master -> Reducer: streams the sorted output retained in merged output mode
+	FetchOutput(*FetchOutputRequest, grpc.ServerStreamingServer[OutputBatch]) error
+	// Mapper -> Reducer: agrees on the encoding of the batches sent on this connection
+	Negotiate(context.Context, *NegotiateRequest) (*NegotiateResponse, error)
+	// Master -> Mapper: reads byte ranges of the input from a shared path, instead of receiving a chunk
+	AssignSplit(context.Context, *AssignSplitRequest) (*AssignSplitResponse, error)
+	// Master -> Reducer: returns the values at ranks of the sorted values retained by a quantiles job
+	QueryRanks(context.Context, *QueryRanksRequest) (*QueryRanksResponse, error)
 	mustEmbedUnimplementedWorkerServiceServer()
 }
 
@@ -119,6 +203,21 @@ func (UnimplementedWorkerServiceServer) SendMappedData(context.Context, *SendMap
 func (UnimplementedWorkerServiceServer) NotifyMapperDone(context.Context, *NotifyMapperDoneRequest) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method NotifyMapperDone not implemented")
 }
+func (UnimplementedWorkerServiceServer) GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedWorkerServiceServer) FetchOutput(*FetchOutputRequest, grpc.ServerStreamingServer[OutputBatch]) error {
+	return status.Errorf(codes.Unimplemented, "method FetchOutput not implemented")
+}
+func (UnimplementedWorkerServiceServer) Negotiate(context.Context, *NegotiateRequest) (*NegotiateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Negotiate not implemented")
+}
+func (UnimplementedWorkerServiceServer) AssignSplit(context.Context, *AssignSplitRequest) (*AssignSplitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignSplit not implemented")
+}
+func (UnimplementedWorkerServiceServer) QueryRanks(context.Context, *QueryRanksRequest) (*QueryRanksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryRanks not implemented")
+}
 func (UnimplementedWorkerServiceServer) mustEmbedUnimplementedWorkerServiceServer() {}
 func (UnimplementedWorkerServiceServer) testEmbeddedByValue()                       {}
 
@@ -212,6 +311,89 @@ func _WorkerService_NotifyMapperDone_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkerService_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServiceServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkerService_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServiceServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkerService_FetchOutput_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FetchOutputRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkerServiceServer).FetchOutput(m, &grpc.GenericServerStream[FetchOutputRequest, OutputBatch]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WorkerService_FetchOutputServer = grpc.ServerStreamingServer[OutputBatch]
+
+func _WorkerService_Negotiate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NegotiateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServiceServer).Negotiate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkerService_Negotiate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServiceServer).Negotiate(ctx, req.(*NegotiateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkerService_AssignSplit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignSplitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServiceServer).AssignSplit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkerService_AssignSplit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServiceServer).AssignSplit(ctx, req.(*AssignSplitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkerService_QueryRanks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRanksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServiceServer).QueryRanks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkerService_QueryRanks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServiceServer).QueryRanks(ctx, req.(*QueryRanksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WorkerService_ServiceDesc is the grpc.ServiceDesc for WorkerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -235,7 +417,29 @@ var WorkerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "NotifyMapperDone",
 			Handler:    _WorkerService_NotifyMapperDone_Handler,
 		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _WorkerService_GetStatus_Handler,
+		},
+		{
+			MethodName: "Negotiate",
+			Handler:    _WorkerService_Negotiate_Handler,
+		},
+		{
+			MethodName: "AssignSplit",
+			Handler:    _WorkerService_AssignSplit_Handler,
+		},
+		{
+			MethodName: "QueryRanks",
+			Handler:    _WorkerService_QueryRanks_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "FetchOutput",
+			Handler:       _WorkerService_FetchOutput_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/mapreduce.proto",
 }
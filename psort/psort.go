@@ -0,0 +1,111 @@
+package psort
+
+import (
+	"math"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+const (
+	// radixBits is the width of the digit sorted by each pass
+	radixBits = 8
+	buckets   = 1 << radixBits
+	passes    = 64 / radixBits
+	// minRadix is the size under which a comparison sort is faster
+	minRadix = 1 << 14
+	// minPerThread bounds the number of threads for small inputs
+	minPerThread = 1 << 13
+)
+
+// Threads resolves a requested thread count, 0 or less means GOMAXPROCS.
+func Threads(n int) int {
+	if n <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return n
+}
+
+// Int64s sorts values in increasing order using a parallel LSD radix sort on threads goroutines,
+// 0 meaning GOMAXPROCS. It needs a scratch buffer as large as values.
+func Int64s(values []int64, threads int) {
+	threads = Threads(threads)
+	if len(values) < minRadix {
+		slices.Sort(values)
+		return
+	}
+	if limit := len(values) / minPerThread; threads > limit {
+		threads = limit
+	}
+
+	// flipping the sign bit orders the digits of int64 like those of uint64
+	flipSign(values, threads)
+	src, dst := values, make([]int64, len(values))
+
+	counts := make([][buckets]int, threads)
+	for pass := 0; pass < passes; pass++ {
+		shift := uint(pass * radixBits)
+		parallel(len(src), threads, func(t, lo, hi int) {
+			c := &counts[t]
+			*c = [buckets]int{}
+			for _, k := range src[lo:hi] {
+				c[(uint64(k)>>shift)&(buckets-1)]++
+			}
+		})
+		// offsets of each thread's values within each bucket, in thread order to keep the sort stable
+		offset := 0
+		uniform := false
+		for b := 0; b < buckets; b++ {
+			total := 0
+			for t := range counts {
+				n := counts[t][b]
+				counts[t][b] = offset + total
+				total += n
+			}
+			if total == len(src) {
+				uniform = true
+			}
+			offset += total
+		}
+		if uniform {
+			// every key has the same digit, the pass would not move anything
+			continue
+		}
+		parallel(len(src), threads, func(t, lo, hi int) {
+			c := &counts[t]
+			for _, k := range src[lo:hi] {
+				b := (uint64(k) >> shift) & (buckets - 1)
+				dst[c[b]] = k
+				c[b]++
+			}
+		})
+		src, dst = dst, src
+	}
+
+	if &src[0] != &values[0] {
+		copy(values, src)
+	}
+	flipSign(values, threads)
+}
+
+func flipSign(values []int64, threads int) {
+	parallel(len(values), threads, func(_, lo, hi int) {
+		for i := lo; i < hi; i++ {
+			values[i] ^= math.MinInt64
+		}
+	})
+}
+
+// parallel calls fn on threads goroutines, each with the index of its contiguous range of [0, n)
+func parallel(n, threads int, fn func(t, lo, hi int)) {
+	var wg sync.WaitGroup
+	for t := 0; t < threads; t++ {
+		lo, hi := n*t/threads, n*(t+1)/threads
+		wg.Add(1)
+		go func(t int) {
+			defer wg.Done()
+			fn(t, lo, hi)
+		}(t)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,124 @@
+package psort
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"slices"
+	"sort"
+	"testing"
+
+	"mapreduce/ioformat"
+)
+
+func random(n int, seed int64) []int64 {
+	r := rand.New(rand.NewSource(seed))
+	values := make([]int64, n)
+	for i := range values {
+		values[i] = int64(r.Uint64())
+	}
+	return values
+}
+
+func repeat(v int64, n int) []int64 {
+	values := make([]int64, n)
+	for i := range values {
+		values[i] = v
+	}
+	return values
+}
+
+func TestInt64s(t *testing.T) {
+	extremes := random(3*minRadix, 2)
+	for i := range extremes {
+		switch i % 5 {
+		case 0:
+			extremes[i] = math.MinInt64
+		case 1:
+			extremes[i] = math.MaxInt64
+		case 2:
+			extremes[i] = 0
+		}
+	}
+	// only the lowest byte differs, so every other pass is skipped
+	lowByte := random(2*minRadix, 3)
+	for i := range lowByte {
+		lowByte[i] = -1<<40 | lowByte[i]&0xff
+	}
+	tests := []struct {
+		name   string
+		values []int64
+	}{
+		{"empty", nil},
+		{"one value", []int64{7}},
+		{"random below minRadix", random(minRadix-1, 4)},
+		{"random at minRadix", random(minRadix, 5)},
+		{"random above minRadix", random(minRadix+1, 6)},
+		{"random", random(1<<17, 7)},
+		{"all equal", repeat(42, 2*minRadix)},
+		{"all equal negative", repeat(-1, 2*minRadix)},
+		{"low byte", lowByte},
+		{"MinInt64 and MaxInt64", extremes},
+		{"sorted", func() []int64 { v := random(2*minRadix, 8); slices.Sort(v); return v }()},
+		{"reversed", func() []int64 { v := random(2*minRadix, 9); slices.Sort(v); slices.Reverse(v); return v }()},
+	}
+	for _, tt := range tests {
+		want := slices.Clone(tt.values)
+		slices.Sort(want)
+		// 64 threads is above len/minPerThread for every input, which must lower it
+		for _, threads := range []int{0, 1, 3, 64} {
+			t.Run(fmt.Sprintf("%s/%d threads", tt.name, threads), func(t *testing.T) {
+				got := slices.Clone(tt.values)
+				Int64s(got, threads)
+				if !slices.Equal(got, want) {
+					t.Errorf("Int64s did not sort %d values", len(got))
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkInt64s compares psort with sort.Slice and slices.Sort on the repository's input file,
+// when it exists, and on random values, run with go test ./psort -run=NONE -bench=Int64s
+func BenchmarkInt64s(b *testing.B) {
+	type dataset struct {
+		name   string
+		values []int64
+	}
+	var datasets []dataset
+	if f, err := os.Open("../input"); err == nil {
+		values, err := ioformat.ReadAll(f, ioformat.Auto, ioformat.Int64)
+		f.Close()
+		if err != nil {
+			b.Fatalf("read ../input: %v", err)
+		}
+		datasets = append(datasets, dataset{"input", values})
+	}
+	for _, n := range []int{1 << 10, 1 << 16, 1 << 20, 10_000_000} {
+		datasets = append(datasets, dataset{fmt.Sprintf("random %d", n), random(n, 1)})
+	}
+	sorts := []struct {
+		name string
+		sort func([]int64)
+	}{
+		{"sort.Slice", func(v []int64) { sort.Slice(v, func(i, j int) bool { return v[i] < v[j] }) }},
+		{"slices.Sort", slices.Sort[[]int64]},
+		{"psort", func(v []int64) { Int64s(v, 0) }},
+		{"psort 1 thread", func(v []int64) { Int64s(v, 1) }},
+	}
+	for _, d := range datasets {
+		v := make([]int64, len(d.values))
+		for _, s := range sorts {
+			b.Run(d.name+"/"+s.name, func(b *testing.B) {
+				b.SetBytes(int64(8 * len(v)))
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					copy(v, d.values)
+					b.StartTimer()
+					s.sort(v)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package tracing
+
+import (
+	"context"
+	"log/slog"
+	"path"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// metadata keys carrying the caller's span
+const (
+	traceIDKey = "x-trace-id"
+	spanIDKey  = "x-parent-span-id"
+)
+
+// UnaryServerInterceptor continues the caller's trace with a span for the handled RPC,
+// and flushes the trace file once the handler returns if it called FlushOnEnd.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, span := startServerSpan(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		endServerSpan(ctx, span, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor records a span covering the whole stream.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startServerSpan(ss.Context(), info.FullMethod)
+		err := handler(srv, &tracedStream{ServerStream: ss, ctx: ctx})
+		endServerSpan(ctx, span, err)
+		return err
+	}
+}
+
+type tracedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tracedStream) Context() context.Context { return s.ctx }
+
+type flushKey struct{}
+
+// FlushOnEnd asks the server interceptors to flush the trace of the RPC handled with ctx once it
+// ends, for the RPC that completes the work of this process in the trace.
+func FlushOnEnd(ctx context.Context) {
+	if flush, ok := ctx.Value(flushKey{}).(*atomic.Bool); ok {
+		flush.Store(true)
+	}
+}
+
+func startServerSpan(ctx context.Context, method string) (context.Context, *Span) {
+	parent := &Span{}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(traceIDKey); len(v) > 0 {
+			parent.TraceID = v[0]
+		}
+		if v := md.Get(spanIDKey); len(v) > 0 {
+			parent.ID = v[0]
+		}
+	}
+	if parent.TraceID == "" {
+		parent.TraceID = NewID()
+	}
+	ctx = context.WithValue(ctx, flushKey{}, new(atomic.Bool))
+	return start(ctx, parent, path.Base(method), nil)
+}
+
+func endServerSpan(ctx context.Context, span *Span, err error) {
+	if err != nil {
+		span.SetAttrs("error", err.Error())
+	}
+	span.End()
+	if !ctx.Value(flushKey{}).(*atomic.Bool).Load() {
+		return
+	}
+	if err := Flush(span.TraceID); err != nil {
+		slog.Warn("Failed to write trace", "trace", span.TraceID, "error", err)
+	}
+}
+
+// UnaryClientInterceptor passes the span in ctx to the callee.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(propagate(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor passes the span in ctx to the callee of a stream.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(propagate(ctx), desc, cc, method, opts...)
+	}
+}
+
+func propagate(ctx context.Context) context.Context {
+	if s := FromContext(ctx); s != nil {
+		ctx = metadata.AppendToOutgoingContext(ctx, traceIDKey, s.TraceID, spanIDKey, s.ID)
+	}
+	return ctx
+}
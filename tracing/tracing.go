@@ -0,0 +1,260 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Span is a timed operation belonging to a trace.
+type Span struct {
+	TraceID   string
+	ID        string
+	ParentID  string
+	Name      string
+	StartTime time.Time
+	EndTime   time.Time
+	Attrs     map[string]any
+
+	track int // timeline row inside this process, shared by local descendants
+}
+
+type spanKey struct{}
+
+var (
+	mu        sync.Mutex
+	dir       string // empty when tracing is disabled
+	process   string
+	spans     = map[string][]*Span{} // finished spans by trace ID
+	tracks    = map[int]string{}     // track names, by track
+	lastTrack int
+
+	flushMu sync.Mutex // serializes the rewrites of trace files
+)
+
+// Configure enables recording and names the files written by Flush after this process.
+// An empty dir disables recording, spans are still propagated.
+func Configure(traceDir, processName string) {
+	mu.Lock()
+	defer mu.Unlock()
+	dir = traceDir
+	process = processName
+}
+
+// NewID returns a random identifier usable as a trace or span ID.
+func NewID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%016x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// StartTrace starts the root span of a new trace with the given ID.
+func StartTrace(ctx context.Context, traceID, name string, attrs ...any) (context.Context, *Span) {
+	return start(ctx, &Span{TraceID: traceID}, name, attrs)
+}
+
+// Start starts a child of the span in ctx. Without a span in ctx it starts a new trace.
+func Start(ctx context.Context, name string, attrs ...any) (context.Context, *Span) {
+	parent := FromContext(ctx)
+	if parent == nil {
+		parent = &Span{TraceID: NewID()}
+	}
+	return start(ctx, parent, name, attrs)
+}
+
+// StartTrack is like Start, but gives the span its own timeline row so that
+// it can run concurrently with its siblings.
+func StartTrack(ctx context.Context, name string, attrs ...any) (context.Context, *Span) {
+	parent := &Span{TraceID: NewID()}
+	if p := FromContext(ctx); p != nil {
+		parent = &Span{TraceID: p.TraceID, ID: p.ID}
+	}
+	return start(ctx, parent, name, attrs)
+}
+
+// FromContext returns the current span, or nil.
+func FromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanKey{}).(*Span)
+	return s
+}
+
+func start(ctx context.Context, parent *Span, name string, attrs []any) (context.Context, *Span) {
+	s := &Span{
+		TraceID:   parent.TraceID,
+		ID:        NewID(),
+		ParentID:  parent.ID,
+		Name:      name,
+		StartTime: time.Now(),
+		Attrs:     map[string]any{},
+		track:     parent.track,
+	}
+	if s.track == 0 {
+		// first span of this process in the call chain gets its own row, when spans are recorded
+		mu.Lock()
+		if dir != "" {
+			lastTrack++
+			s.track = lastTrack
+			tracks[s.track] = name
+		}
+		mu.Unlock()
+	}
+	s.SetAttrs(attrs...)
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// SetAttrs adds key/value pairs to the span, shown as event args in the trace viewer.
+func (s *Span) SetAttrs(kv ...any) {
+	for i := 0; i+1 < len(kv); i += 2 {
+		s.Attrs[fmt.Sprint(kv[i])] = kv[i+1]
+	}
+}
+
+// End records the span if tracing is enabled.
+func (s *Span) End() {
+	s.EndTime = time.Now()
+	mu.Lock()
+	defer mu.Unlock()
+	if dir == "" {
+		return
+	}
+	spans[s.TraceID] = append(spans[s.TraceID], s)
+}
+
+// Event is an entry of the Chrome trace-event format.
+type Event struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat,omitempty"`
+	Ph   string         `json:"ph"`
+	Ts   int64          `json:"ts"`
+	Dur  int64          `json:"dur,omitempty"`
+	Pid  uint32         `json:"pid"`
+	Tid  int            `json:"tid"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// File is the JSON object format accepted by chrome://tracing and Perfetto.
+type File struct {
+	TraceEvents     []Event `json:"traceEvents"`
+	DisplayTimeUnit string  `json:"displayTimeUnit,omitempty"`
+}
+
+// Flush adds the spans recorded for traceID since its last flush to
+// <dir>/trace-<traceID>-<process>.json, then forgets them along with their tracks.
+// Processes call it once a trace is complete, spans ending later are kept for the next flush.
+func Flush(traceID string) error {
+	mu.Lock()
+	if dir == "" {
+		mu.Unlock()
+		return nil
+	}
+	path := filepath.Join(dir, fmt.Sprintf("trace-%s-%s.json", traceID, safeName(process)))
+	recorded := spans[traceID]
+	names := map[int]string{}
+	for _, s := range recorded {
+		if _, ok := names[s.track]; !ok {
+			names[s.track] = tracks[s.track]
+			delete(tracks, s.track)
+		}
+	}
+	delete(spans, traceID)
+	pid := processID(process)
+	name := process
+	mu.Unlock()
+	if len(recorded) == 0 {
+		return nil
+	}
+
+	flushMu.Lock()
+	defer flushMu.Unlock()
+	file := File{DisplayTimeUnit: "ms"}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &file); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	} else {
+		file.TraceEvents = append(file.TraceEvents, Event{
+			Name: "process_name", Ph: "M", Pid: pid, Args: map[string]any{"name": name},
+		})
+	}
+	named := map[int]bool{}
+	for _, s := range recorded {
+		if !named[s.track] {
+			named[s.track] = true
+			file.TraceEvents = append(file.TraceEvents, Event{
+				Name: "thread_name", Ph: "M", Pid: pid, Tid: s.track,
+				Args: map[string]any{"name": fmt.Sprintf("%s #%d", names[s.track], s.track)},
+			})
+		}
+		args := map[string]any{"span_id": s.ID}
+		if s.ParentID != "" {
+			args["parent_id"] = s.ParentID
+		}
+		for k, v := range s.Attrs {
+			args[k] = v
+		}
+		file.TraceEvents = append(file.TraceEvents, Event{
+			Name: s.Name,
+			Cat:  "mapreduce",
+			Ph:   "X",
+			Ts:   s.StartTime.UnixMicro(),
+			Dur:  s.EndTime.Sub(s.StartTime).Microseconds(),
+			Pid:  pid,
+			Tid:  s.track,
+			Args: args,
+		})
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Merge combines the trace files of several processes into one timeline.
+// Timestamps are absolute, so events line up as long as the clocks are in sync.
+func Merge(out string, inputs []string) error {
+	merged := File{DisplayTimeUnit: "ms"}
+	for _, in := range inputs {
+		data, err := os.ReadFile(in)
+		if err != nil {
+			return err
+		}
+		var f File
+		if err := json.Unmarshal(data, &f); err != nil {
+			return fmt.Errorf("%s: %w", in, err)
+		}
+		merged.TraceEvents = append(merged.TraceEvents, f.TraceEvents...)
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, data, 0o644)
+}
+
+// processID derives a stable pid so that merged files keep processes apart.
+func processID(name string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return h.Sum32() >> 1
+}
+
+func safeName(name string) string {
+	return strings.NewReplacer(":", "_", "/", "_").Replace(name)
+}
@@ -0,0 +1,162 @@
+package worker
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	pb "mapreduce/proto"
+)
+
+// fallbacks lists the encodings a mapper offers for each preferred encoding, in order of preference.
+// BATCH_RAW is always last so that reducers without encoding support can still be reached.
+var fallbacks = map[pb.BatchEncoding][]pb.BatchEncoding{
+	pb.BatchEncoding_BATCH_RAW:                {pb.BatchEncoding_BATCH_RAW},
+	pb.BatchEncoding_BATCH_DELTA_VARINT:       {pb.BatchEncoding_BATCH_DELTA_VARINT, pb.BatchEncoding_BATCH_RAW},
+	pb.BatchEncoding_BATCH_DELTA_VARINT_FLATE: {pb.BatchEncoding_BATCH_DELTA_VARINT_FLATE, pb.BatchEncoding_BATCH_DELTA_VARINT, pb.BatchEncoding_BATCH_RAW},
+}
+
+// Negotiate picks the first encoding offered by the mapper that this reducer can decode.
+func (ws *WorkerServer) Negotiate(ctx context.Context, req *pb.NegotiateRequest) (*pb.NegotiateResponse, error) {
+	for _, enc := range req.Encodings {
+		if _, ok := fallbacks[enc]; ok {
+			return &pb.NegotiateResponse{Encoding: enc}, nil
+		}
+	}
+	return &pb.NegotiateResponse{Encoding: pb.BatchEncoding_BATCH_RAW}, nil
+}
+
+// peer is a connection from a mapper to a reducer, with the batch encoding agreed on it
+type peer struct {
+	addr     string
+	conn     *grpc.ClientConn
+	client   pb.WorkerServiceClient
+	encoding pb.BatchEncoding
+}
+
+// connect opens a connection to the reducer at addr and negotiates the batch encoding.
+// Reducers that do not implement Negotiate receive raw batches.
+func (ws *WorkerServer) connect(ctx context.Context, addr string) (*peer, error) {
+	conn, err := ws.dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	p := &peer{addr: addr, conn: conn, client: pb.NewWorkerServiceClient(conn)}
+	if ws.encoding == pb.BatchEncoding_BATCH_RAW {
+		return p, nil
+	}
+	resp, err := p.client.Negotiate(ctx, &pb.NegotiateRequest{Encodings: fallbacks[ws.encoding]})
+	switch {
+	case status.Code(err) == codes.Unimplemented:
+	case err != nil:
+		conn.Close()
+		return nil, err
+	default:
+		p.encoding = resp.Encoding
+	}
+	ws.log().Debug("Negotiated batch encoding", "phase", "shuffle", "reducer", addr, "encoding", p.encoding)
+	return p, nil
+}
+
+func (p *peer) close() error {
+	return p.conn.Close()
+}
+
+// encodeBatch encodes sorted values as zigzag varint deltas, each value relative to the previous one,
+// then compresses them if the encoding asks for it. Unsorted values are valid but encode poorly.
+func encodeBatch(values []int64, enc pb.BatchEncoding) (*pb.EncodedBatch, error) {
+	data := make([]byte, 0, len(values)*2)
+	var prev int64
+	for _, v := range values {
+		data = binary.AppendVarint(data, v-prev)
+		prev = v
+	}
+	if enc == pb.BatchEncoding_BATCH_DELTA_VARINT_FLATE {
+		var buf bytes.Buffer
+		fw, err := flate.NewWriter(&buf, flate.BestSpeed)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fw.Write(data); err != nil {
+			return nil, err
+		}
+		if err := fw.Close(); err != nil {
+			return nil, err
+		}
+		data = buf.Bytes()
+	}
+	return &pb.EncodedBatch{Encoding: enc, Count: int64(len(values)), Data: data}, nil
+}
+
+// decodeBatch reverses encodeBatch. Compressed batches may not inflate to more than the
+// largest varints of their count of values.
+func decodeBatch(b *pb.EncodedBatch) ([]int64, error) {
+	if b.Count < 0 || b.Count > math.MaxInt64/binary.MaxVarintLen64 {
+		return nil, errors.New("invalid batch value count")
+	}
+	data := b.Data
+	switch b.Encoding {
+	case pb.BatchEncoding_BATCH_DELTA_VARINT:
+	case pb.BatchEncoding_BATCH_DELTA_VARINT_FLATE:
+		limit := b.Count * binary.MaxVarintLen64
+		var err error
+		data, err = io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(b.Data)), limit+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) > limit {
+			return nil, fmt.Errorf("batch inflates to more than %d bytes for %d values", limit, b.Count)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported batch encoding %v", b.Encoding)
+	}
+	if b.Count > int64(len(data)) {
+		return nil, errors.New("invalid batch value count")
+	}
+	values := make([]int64, 0, b.Count)
+	var prev int64
+	for len(data) > 0 {
+		delta, n := binary.Varint(data)
+		if n <= 0 {
+			return nil, errors.New("malformed varint in batch")
+		}
+		prev += delta
+		values = append(values, prev)
+		data = data[n:]
+	}
+	if int64(len(values)) != b.Count {
+		return nil, fmt.Errorf("batch holds %d values, expected %d", len(values), b.Count)
+	}
+	return values, nil
+}
+
+// peers holds the connections of a mapper to reducers, opened on first use
+type peers map[string]*peer
+
+func (ps peers) get(ctx context.Context, ws *WorkerServer, addr string) (*peer, error) {
+	if p, ok := ps[addr]; ok {
+		return p, nil
+	}
+	p, err := ws.connect(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	ps[addr] = p
+	return p, nil
+}
+
+func (ps peers) close(ws *WorkerServer) {
+	for addr, p := range ps {
+		if err := p.close(); err != nil {
+			ws.log().Warn("Failed to close connection", "peer", addr, "error", err)
+		}
+	}
+}
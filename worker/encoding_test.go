@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"bytes"
+	"compress/flate"
+	"math"
+	"slices"
+	"testing"
+
+	pb "mapreduce/proto"
+)
+
+func TestBatchRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int64
+	}{
+		{"empty", nil},
+		{"sorted", []int64{-5, -5, 0, 1, 1000, 1 << 40}},
+		// deltas between the extremes overflow int64 and must wrap back
+		{"extremes", []int64{math.MinInt64, math.MaxInt64}},
+		{"extremes reversed", []int64{math.MaxInt64, math.MinInt64, math.MaxInt64, 0, math.MinInt64}},
+		{"unsorted", []int64{3, -1, math.MinInt64 + 1, 42, math.MaxInt64 - 1, -math.MaxInt64}},
+	}
+	for _, enc := range []pb.BatchEncoding{pb.BatchEncoding_BATCH_DELTA_VARINT, pb.BatchEncoding_BATCH_DELTA_VARINT_FLATE} {
+		for _, tt := range tests {
+			b, err := encodeBatch(tt.values, enc)
+			if err != nil {
+				t.Fatalf("%s %s: encodeBatch: %v", enc, tt.name, err)
+			}
+			got, err := decodeBatch(b)
+			if err != nil {
+				t.Fatalf("%s %s: decodeBatch: %v", enc, tt.name, err)
+			}
+			if !slices.Equal(got, tt.values) {
+				t.Errorf("%s %s: decoded %v, want %v", enc, tt.name, got, tt.values)
+			}
+		}
+	}
+}
+
+func TestDecodeBatchRejects(t *testing.T) {
+	// a few hundred bytes that inflate to 1 MiB of zero deltas
+	var buf bytes.Buffer
+	fw, _ := flate.NewWriter(&buf, flate.BestCompression)
+	fw.Write(make([]byte, 1<<20))
+	fw.Close()
+	valid, _ := encodeBatch([]int64{1, 2, 3}, pb.BatchEncoding_BATCH_DELTA_VARINT)
+	tests := []struct {
+		name  string
+		batch *pb.EncodedBatch
+	}{
+		{"inflates beyond its count", &pb.EncodedBatch{Encoding: pb.BatchEncoding_BATCH_DELTA_VARINT_FLATE, Count: 10, Data: buf.Bytes()}},
+		{"negative count", &pb.EncodedBatch{Encoding: pb.BatchEncoding_BATCH_DELTA_VARINT, Count: -1, Data: valid.Data}},
+		{"overflowing count", &pb.EncodedBatch{Encoding: pb.BatchEncoding_BATCH_DELTA_VARINT_FLATE, Count: math.MaxInt64, Data: buf.Bytes()}},
+		{"fewer values than count", &pb.EncodedBatch{Encoding: pb.BatchEncoding_BATCH_DELTA_VARINT, Count: 2, Data: valid.Data}},
+		{"truncated varint", &pb.EncodedBatch{Encoding: pb.BatchEncoding_BATCH_DELTA_VARINT, Count: 1, Data: []byte{0x80}}},
+		{"raw", &pb.EncodedBatch{Encoding: pb.BatchEncoding_BATCH_RAW, Count: 3, Data: valid.Data}},
+	}
+	for _, tt := range tests {
+		if values, err := decodeBatch(tt.batch); err == nil {
+			t.Errorf("%s: decodeBatch = %d values, want an error", tt.name, len(values))
+		}
+	}
+}
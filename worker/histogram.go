@@ -0,0 +1,150 @@
+package worker
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"mapreduce/ioformat"
+	"mapreduce/keys"
+	pb "mapreduce/proto"
+	"mapreduce/tracing"
+)
+
+// histogram is a run of distinct sorted values with the number of occurrences of each
+type histogram struct {
+	values, counts []int64
+}
+
+// countValues counts the occurrences of sorted values, and returns the distinct values with their counts.
+// The distinct values reuse the array of values.
+func countValues(values []int64) ([]int64, []int64) {
+	var counts []int64
+	distinct := values[:0]
+	for i, v := range values {
+		if i > 0 && v == distinct[len(distinct)-1] {
+			counts[len(counts)-1]++
+			continue
+		}
+		distinct = append(distinct, v)
+		counts = append(counts, 1)
+	}
+	return distinct, counts
+}
+
+// countSum returns the number of values counted by counts
+func countSum(counts []int64) int64 {
+	var n int64
+	for _, c := range counts {
+		n += c
+	}
+	return n
+}
+
+// finalizeHistogram merges the histograms received from mappers, then writes or retains them. ws.mu must be held.
+func (ws *WorkerServer) finalizeHistogram(ctx context.Context) {
+	logger := ws.log()
+	logger.Info("All mappers done, reducing", "phase", "reduce", "runs", len(ws.histograms))
+	_, span := tracing.Start(ctx, "merge", "runs", len(ws.histograms))
+	sortStart := time.Now()
+	values, counts := mergeHistograms(ws.histograms)
+	if ws.order.Descending {
+		slices.Reverse(values)
+		slices.Reverse(counts)
+	}
+	sortDuration.With("reducer").Observe(time.Since(sortStart).Seconds())
+	span.SetAttrs("values", len(values))
+	span.End()
+	ws.histograms = nil
+	reducerQueueSize.Set(0)
+
+	if ws.outputMode == pb.OutputMode_OUTPUT_MERGED {
+		ws.retained, ws.retainedCounts = values, counts
+		part := ws.partInfo(len(values), func(i int) int64 { return values[i] })
+		part.Values = countSum(counts)
+		ws.progress.part.Store(part)
+		ws.progress.done.Store(true)
+		logger.Info("Output ready to be fetched", "phase", "write", "records", len(values), "values", part.Values)
+		return
+	}
+
+	_, span = tracing.Start(ctx, "write file")
+	part, err := ws.writePart(values, counts, nil)
+	span.End()
+	if err != nil {
+		logger.Error("Failed to write output part", "phase", "write", "error", err)
+		ws.progress.fail(fmt.Errorf("write output part: %w", err))
+		return
+	}
+	ws.progress.part.Store(part)
+	ws.progress.done.Store(true)
+	logger.Info("Wrote output", "phase", "write", "path", part.Path, "records", part.Records, "values", part.Values)
+}
+
+// histogramHeap orders the smallest values of histograms
+type histogramHeap struct {
+	runs  []histogram
+	heads []int // index of the runs that are not exhausted
+}
+
+func (h *histogramHeap) Len() int { return len(h.heads) }
+func (h *histogramHeap) Less(i, j int) bool {
+	return h.runs[h.heads[i]].values[0] < h.runs[h.heads[j]].values[0]
+}
+func (h *histogramHeap) Swap(i, j int) { h.heads[i], h.heads[j] = h.heads[j], h.heads[i] }
+func (h *histogramHeap) Push(x any)    { h.heads = append(h.heads, x.(int)) }
+func (h *histogramHeap) Pop() any {
+	x := h.heads[len(h.heads)-1]
+	h.heads = h.heads[:len(h.heads)-1]
+	return x
+}
+
+// mergeHistograms merges sorted histograms into one, adding up the counts of the values they share
+func mergeHistograms(runs []histogram) (values, counts []int64) {
+	h := &histogramHeap{runs: runs}
+	for i, run := range runs {
+		if len(run.values) > 0 {
+			h.heads = append(h.heads, i)
+		}
+	}
+	heap.Init(h)
+	for h.Len() > 0 {
+		run := &runs[h.heads[0]]
+		v, c := run.values[0], run.counts[0]
+		if n := len(values); n > 0 && values[n-1] == v {
+			counts[n-1] += c
+		} else {
+			values = append(values, v)
+			counts = append(counts, c)
+		}
+		run.values, run.counts = run.values[1:], run.counts[1:]
+		if len(run.values) == 0 {
+			heap.Pop(h)
+		} else {
+			heap.Fix(h, 0)
+		}
+	}
+	return values, counts
+}
+
+// writeHistogram writes a line per value with its count
+func (ws *WorkerServer) writeHistogram(out io.Writer, values, counts []int64) error {
+	zw, closer := ioformat.Compress(out, ws.compressOutput)
+	w := ioformat.NewRecordWriter(zw)
+	t := keys.Numeric(ws.order.Type)
+	var line []byte
+	for i, v := range values {
+		line = t.AppendCount(line[:0], v, counts[i])
+		if err := w.Write(line); err != nil {
+			return err
+		}
+		ws.progress.valuesWritten.Add(counts[i])
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return closer.Close()
+}
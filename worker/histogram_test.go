@@ -0,0 +1,56 @@
+package worker
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestCountValues(t *testing.T) {
+	tests := []struct {
+		values, distinct, counts []int64
+	}{
+		{nil, nil, nil},
+		{[]int64{7}, []int64{7}, []int64{1}},
+		{[]int64{3, 3, 3}, []int64{3}, []int64{3}},
+		{[]int64{math.MinInt64, -1, -1, 0, 2, 2, 2, math.MaxInt64}, []int64{math.MinInt64, -1, 0, 2, math.MaxInt64}, []int64{1, 2, 1, 3, 1}},
+	}
+	for _, tt := range tests {
+		values := slices.Clone(tt.values)
+		distinct, counts := countValues(values)
+		if !slices.Equal(distinct, tt.distinct) || !slices.Equal(counts, tt.counts) {
+			t.Errorf("countValues(%v) = %v, %v, want %v, %v", tt.values, distinct, counts, tt.distinct, tt.counts)
+		}
+	}
+}
+
+func TestMergeHistograms(t *testing.T) {
+	tests := []struct {
+		name           string
+		runs           []histogram
+		values, counts []int64
+	}{
+		{"none", nil, nil, nil},
+		{"one run", []histogram{{[]int64{1, 2}, []int64{3, 4}}}, []int64{1, 2}, []int64{3, 4}},
+		{"shared values", []histogram{
+			{[]int64{1, 3, 5}, []int64{1, 2, 3}},
+			{[]int64{3, 4, 5}, []int64{10, 20, 30}},
+			{},
+			{[]int64{5}, []int64{100}},
+		}, []int64{1, 3, 4, 5}, []int64{1, 12, 20, 133}},
+		// the last value of a run is the first of the next one
+		{"run boundary", []histogram{
+			{[]int64{-2, 0}, []int64{1, 2}},
+			{[]int64{0, 9}, []int64{5, 1}},
+			{[]int64{9}, []int64{4}},
+		}, []int64{-2, 0, 9}, []int64{1, 7, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, counts := mergeHistograms(tt.runs)
+			if !slices.Equal(values, tt.values) || !slices.Equal(counts, tt.counts) {
+				t.Errorf("mergeHistograms = %v, %v, want %v, %v", values, counts, tt.values, tt.counts)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package worker
+
+import "mapreduce/metrics"
+
+var (
+	chunkValuesReceived = metrics.NewCounter("mapreduce_mapper_values_received_total",
+		"Values received by this worker from the master as a mapper.")
+	mappedValuesReceived = metrics.NewCounter("mapreduce_reducer_values_received_total",
+		"Values received by this worker from mappers as a reducer.")
+	shuffleBytes = metrics.NewCounterVec("mapreduce_shuffle_bytes_total",
+		"Bytes of mapped data sent by this mapper, per destination reducer.", "reducer")
+	shuffleValues = metrics.NewCounterVec("mapreduce_shuffle_values_total",
+		"Values of mapped data sent by this mapper, per destination reducer.", "reducer")
+	spills = metrics.NewCounterVec("mapreduce_mapper_spills_total",
+		"Sub-chunks spilled from a sorted chunk to a reducer.", "reducer")
+	sortDuration = metrics.NewHistogramVec("mapreduce_sort_duration_seconds",
+		"Time spent sorting a chunk (mapper) or the received data (reducer).", nil, "role")
+	reducerQueueSize = metrics.NewGauge("mapreduce_reducer_queue_size",
+		"Values buffered by this reducer waiting for all mappers to finish.")
+	mappersPending = metrics.NewGauge("mapreduce_reducer_mappers_pending",
+		"Mappers this reducer is still waiting for.")
+)
@@ -0,0 +1,182 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"mapreduce/ioformat"
+	"mapreduce/keys"
+	pb "mapreduce/proto"
+	"mapreduce/tracing"
+)
+
+const (
+	// progressStep is how many values are written between progress updates
+	progressStep = 1 << 16
+	// fetchBatch is how many values are streamed per message by FetchOutput
+	fetchBatch = 1 << 16
+	// fetchBytes bounds the payload bytes of the records streamed per message by FetchOutput
+	fetchBytes = 1 << 20
+	// MaxMessageSize is the largest message a worker accepts, chunks and record batches can be large
+	MaxMessageSize = 1 << 30
+)
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// PartName is the file name of the output part of the reducer with the given interval rank.
+func PartName(partition int32) string {
+	return fmt.Sprintf("part-%05d", partition)
+}
+
+// writePart writes sorted values, with their counts in histogram jobs, or the lines of sorted records,
+// to the reducer's part file in the output directory. The file only appears under its final name once
+// it is complete, and is removed if it cannot be.
+func (ws *WorkerServer) writePart(values, counts []int64, records []*pb.Record) (_ *pb.PartInfo, err error) {
+	if err := os.MkdirAll(ws.outputDir, 0o755); err != nil {
+		return nil, err
+	}
+	name := PartName(ws.part)
+	if ws.compressOutput {
+		name += ioformat.GzipSuffix
+	}
+	path := filepath.Join(ws.outputDir, name)
+	tmp := filepath.Join(ws.outputDir, "."+name+".inprogress")
+	f, err := os.Create(tmp)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(tmp)
+		}
+	}()
+
+	sum := crc32.New(crc32c)
+	out := io.MultiWriter(countingWriter{f, &ws.progress.bytesWritten}, sum)
+	var part *pb.PartInfo
+	switch {
+	case ws.records:
+		err = ws.writeRecords(out, records)
+		part = ws.recordPartInfo(records)
+	case counts != nil:
+		err = ws.writeHistogram(out, values, counts)
+		part = ws.partInfo(len(values), func(i int) int64 { return values[i] })
+		part.Values = countSum(counts)
+	default:
+		err = ws.writeValues(out, values)
+		part = ws.partInfo(len(values), func(i int) int64 { return values[i] })
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, err
+	}
+	if counts != nil {
+		ws.progress.valuesWritten.Store(part.Values)
+	} else {
+		ws.progress.valuesWritten.Store(part.Records)
+	}
+
+	part.Path = path
+	part.Bytes = ws.progress.bytesWritten.Load()
+	part.Crc32C = sum.Sum32()
+	return part, nil
+}
+
+func (ws *WorkerServer) writeValues(out io.Writer, values []int64) error {
+	w, zw, err := ioformat.NewCompressedWriter(out, ws.outputFormat, keys.Numeric(ws.order.Type), ws.compressOutput)
+	if err != nil {
+		return err
+	}
+	for i, v := range values {
+		if err := w.Write(v); err != nil {
+			return err
+		}
+		if i%progressStep == progressStep-1 {
+			ws.progress.valuesWritten.Add(progressStep)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// partInfo describes the n sorted keys of the reducer's output
+func (ws *WorkerServer) partInfo(n int, key func(i int) int64) *pb.PartInfo {
+	part := &pb.PartInfo{
+		Partition: ws.part,
+		Records:   int64(n),
+	}
+	if n > 0 {
+		part.Min = key(0)
+		part.Max = key(n - 1)
+		if ws.order.Descending {
+			part.Min, part.Max = part.Max, part.Min
+		}
+	}
+	return part
+}
+
+// FetchOutput streams the sorted output retained in merged mode, then releases it.
+// Batches are bounded so that neither side holds more than fetchBatch values in flight.
+func (ws *WorkerServer) FetchOutput(req *pb.FetchOutputRequest, stream pb.WorkerService_FetchOutputServer) error {
+	tracing.FlushOnEnd(stream.Context())
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if req.JobId != ws.jobID || !ws.progress.done.Load() || ws.outputMode != pb.OutputMode_OUTPUT_MERGED {
+		return status.Errorf(codes.FailedPrecondition, "no output retained for job %q", req.JobId)
+	}
+	if ws.records {
+		return ws.fetchRecords(stream)
+	}
+	values, counts := ws.retained, ws.retainedCounts
+	for start := 0; start < len(values); start += fetchBatch {
+		end := start + fetchBatch
+		if end > len(values) {
+			end = len(values)
+		}
+		batch := &pb.OutputBatch{Values: values[start:end]}
+		if counts != nil {
+			batch.Counts = counts[start:end]
+		}
+		if err := stream.Send(batch); err != nil {
+			return err
+		}
+	}
+	ws.retained, ws.retainedCounts = nil, nil
+	ws.log().Info("Output fetched", "phase", "write", "records", len(values))
+	return nil
+}
+
+// QueryRanks returns the values at ranks of the sorted values retained by a quantiles job.
+// They are kept until the next job is assigned, so that the master can query them again.
+func (ws *WorkerServer) QueryRanks(ctx context.Context, req *pb.QueryRanksRequest) (*pb.QueryRanksResponse, error) {
+	tracing.FlushOnEnd(ctx)
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if req.JobId != ws.jobID || !ws.progress.done.Load() || ws.job != pb.JobKind_JOB_QUANTILES {
+		return nil, status.Errorf(codes.FailedPrecondition, "no values retained for job %q", req.JobId)
+	}
+	values := make([]int64, len(req.Ranks))
+	for i, rank := range req.Ranks {
+		if rank < 0 || rank >= int64(len(ws.retained)) {
+			return nil, status.Errorf(codes.OutOfRange, "rank %d out of %d values", rank, len(ws.retained))
+		}
+		values[i] = ws.retained[rank]
+	}
+	ws.log().Info("Answered rank query", "phase", "write", "ranks", len(req.Ranks))
+	return &pb.QueryRanksResponse{Values: values}, nil
+}
@@ -0,0 +1,254 @@
+package worker
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"mapreduce/ioformat"
+	"mapreduce/keys"
+	pb "mapreduce/proto"
+	"mapreduce/tracing"
+)
+
+// assignRecordSplits reads the lines of the splits as records, then maps them
+func (ws *WorkerServer) assignRecordSplits(ctx context.Context, req *pb.AssignSplitRequest) (*pb.AssignSplitResponse, error) {
+	parser := keys.Parser{
+		Field:   ioformat.KeyField{Field: int(req.KeyField), Delimiter: req.Delimiter},
+		Type:    req.KeyType,
+		Columns: req.SortKeys,
+	}
+	var records []*pb.Record
+	for _, split := range req.Splits {
+		_, span := tracing.Start(ctx, "read split", "path", split.Path, "offset", split.Offset, "length", split.Length)
+		read, err := readRecordSplit(split, parser, records)
+		span.SetAttrs("records", len(read)-len(records))
+		span.End()
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "read split %s@%d+%d: %v", split.Path, split.Offset, split.Length, err)
+		}
+		ws.log().Info("Read split", "phase", "map", "path", split.Path, "offset", split.Offset,
+			"length", split.Length, "records", len(read)-len(records))
+		chunkValuesReceived.Add(float64(len(read) - len(records)))
+		ws.progress.valuesReceived.Add(int64(len(read) - len(records)))
+		records = read
+	}
+
+	ws.mapRecords(ctx, records)
+	return &pb.AssignSplitResponse{Message: "Mapper finished sending data.", Values: int64(len(records))}, nil
+}
+
+// readRecordSplit appends the records of split to records
+func readRecordSplit(split *pb.InputSplit, parser keys.Parser, records []*pb.Record) ([]*pb.Record, error) {
+	f, err := os.Open(split.Path)
+	if err != nil {
+		return records, err
+	}
+	defer f.Close()
+	r := keys.NewReader(io.NewSectionReader(f, split.Offset, split.Length), parser)
+	// a split has fewer lines than bytes, so seqs follow the order of the splits
+	r.Seq = split.Position
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, rec)
+	}
+}
+
+// mapRecords sorts records by key, sends each reducer its range, then notifies every reducer.
+// The sort is stable, so that records with equal keys keep their order within a batch.
+func (ws *WorkerServer) mapRecords(ctx context.Context, records []*pb.Record) {
+	_, span := tracing.Start(ctx, "sort", "records", len(records))
+	sortStart := time.Now()
+	slices.SortStableFunc(records, ws.order.Compare())
+	sortDuration.With("mapper").Observe(time.Since(sortStart).Seconds())
+	span.End()
+	if ws.unique {
+		// records with equal keys are sorted by seq, the first one left is the first of the input
+		n := len(records)
+		records = slices.CompactFunc(records, ws.order.Equal())
+		ws.progress.duplicates.Add(int64(n - len(records)))
+	}
+
+	_, span = tracing.Start(ctx, "partition")
+	batches := ws.partition(len(records),
+		func(i int) string { return ws.route(records[i]) },
+		func(i int) any { return keys.Format(ws.order.Type, records[i]) })
+	for i := range batches {
+		batches[i].records = records[batches[i].start:batches[i].end]
+	}
+	span.SetAttrs("batches", len(batches))
+	span.End()
+
+	ws.shuffle(ctx, batches)
+}
+
+// route returns the address of the reducer whose interval holds the key of r
+func (ws *WorkerServer) route(r *pb.Record) string {
+	if !keys.Bytes(ws.order.Type) {
+		return ws.findReducer(r.Key)
+	}
+	compare := ws.order.CompareBytes()
+	for i, ri := range ws.reducers {
+		lowest, highest := ws.order.Bounds(i, len(ws.reducers))
+		if (lowest || compare(r.KeyBytes, ri.KeyStart) >= 0) &&
+			(highest || compare(r.KeyBytes, ri.KeyEnd) < 0) {
+			return ri.Address
+		}
+	}
+	return ""
+}
+
+// finalizeRecords merges the sorted runs received from mappers, then writes or retains them. ws.mu must be held.
+func (ws *WorkerServer) finalizeRecords(ctx context.Context) {
+	logger := ws.log()
+	logger.Info("All mappers done, reducing", "phase", "reduce", "records", ws.pendingRecords, "runs", len(ws.runs))
+	_, span := tracing.Start(ctx, "merge", "records", ws.pendingRecords, "runs", len(ws.runs))
+	sortStart := time.Now()
+	var equal func(a, b *pb.Record) bool
+	if ws.unique {
+		equal = ws.order.Equal()
+	}
+	records := mergeRuns(ws.runs, ws.pendingRecords, ws.order.Compare(), equal)
+	ws.progress.duplicates.Add(int64(ws.pendingRecords - len(records)))
+	sortDuration.With("reducer").Observe(time.Since(sortStart).Seconds())
+	span.End()
+	ws.runs = nil
+	ws.pendingRecords = 0
+	reducerQueueSize.Set(0)
+
+	if ws.outputMode == pb.OutputMode_OUTPUT_MERGED {
+		ws.retainedOut = records
+		ws.progress.part.Store(ws.recordPartInfo(records))
+		ws.progress.done.Store(true)
+		logger.Info("Output ready to be fetched", "phase", "write", "records", len(records))
+		return
+	}
+
+	_, span = tracing.Start(ctx, "write file")
+	part, err := ws.writePart(nil, nil, records)
+	span.End()
+	if err != nil {
+		logger.Error("Failed to write output part", "phase", "write", "error", err)
+		ws.progress.fail(fmt.Errorf("write output part: %w", err))
+		return
+	}
+	ws.progress.part.Store(part)
+	ws.progress.done.Store(true)
+	logger.Info("Wrote output", "phase", "write", "path", part.Path, "records", part.Records)
+}
+
+// runHeap orders the heads of sorted runs, ties go to the earlier run
+type runHeap struct {
+	runs    [][]*pb.Record
+	heads   []int // index of the runs that are not exhausted
+	compare func(a, b *pb.Record) int
+}
+
+func (h *runHeap) Len() int { return len(h.heads) }
+func (h *runHeap) Less(i, j int) bool {
+	a, b := h.heads[i], h.heads[j]
+	if c := h.compare(h.runs[a][0], h.runs[b][0]); c != 0 {
+		return c < 0
+	}
+	return a < b
+}
+func (h *runHeap) Swap(i, j int) { h.heads[i], h.heads[j] = h.heads[j], h.heads[i] }
+func (h *runHeap) Push(x any)    { h.heads = append(h.heads, x.(int)) }
+func (h *runHeap) Pop() any {
+	x := h.heads[len(h.heads)-1]
+	h.heads = h.heads[:len(h.heads)-1]
+	return x
+}
+
+// mergeRuns merges sorted runs holding n records in total into a single sorted slice.
+// Records with equal keys keep the order of their runs. When equal is set, only the first
+// of the records it finds equal is kept.
+func mergeRuns(runs [][]*pb.Record, n int, compare func(a, b *pb.Record) int, equal func(a, b *pb.Record) bool) []*pb.Record {
+	h := &runHeap{runs: runs, compare: compare}
+	for i, run := range runs {
+		if len(run) > 0 {
+			h.heads = append(h.heads, i)
+		}
+	}
+	heap.Init(h)
+	out := make([]*pb.Record, 0, n)
+	for h.Len() > 0 {
+		i := h.heads[0]
+		if equal == nil || len(out) == 0 || !equal(out[len(out)-1], runs[i][0]) {
+			out = append(out, runs[i][0])
+		}
+		runs[i] = runs[i][1:]
+		if len(runs[i]) == 0 {
+			heap.Pop(h)
+		} else {
+			heap.Fix(h, 0)
+		}
+	}
+	return out
+}
+
+// recordPartInfo describes sorted records of the reducer's output
+func (ws *WorkerServer) recordPartInfo(records []*pb.Record) *pb.PartInfo {
+	part := ws.partInfo(len(records), func(i int) int64 { return records[i].Key })
+	if keys.Bytes(ws.order.Type) {
+		part.Min, part.Max = 0, 0
+		if len(records) > 0 {
+			part.MinKey = records[0].KeyBytes
+			part.MaxKey = records[len(records)-1].KeyBytes
+			if ws.order.Descending {
+				part.MinKey, part.MaxKey = part.MaxKey, part.MinKey
+			}
+		}
+	}
+	return part
+}
+
+// writeRecords writes the lines of records in order
+func (ws *WorkerServer) writeRecords(out io.Writer, records []*pb.Record) error {
+	zw, closer := ioformat.Compress(out, ws.compressOutput)
+	w := ioformat.NewRecordWriter(zw)
+	for i, r := range records {
+		if err := w.Write(keys.Line(ws.order.Type, r)); err != nil {
+			return err
+		}
+		if i%progressStep == progressStep-1 {
+			ws.progress.valuesWritten.Add(progressStep)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return closer.Close()
+}
+
+// fetchRecords streams the retained records in batches bounded by count and size, then releases them.
+// ws.mu must be held.
+func (ws *WorkerServer) fetchRecords(stream pb.WorkerService_FetchOutputServer) error {
+	records := ws.retainedOut
+	for start := 0; start < len(records); {
+		end, size := start, 0
+		for end < len(records) && end-start < fetchBatch && (end == start || size < fetchBytes) {
+			size += len(records[end].Payload) + len(records[end].KeyBytes)
+			end++
+		}
+		if err := stream.Send(&pb.OutputBatch{Records: records[start:end]}); err != nil {
+			return err
+		}
+		start = end
+	}
+	ws.retainedOut = nil
+	ws.log().Info("Output fetched", "phase", "write", "records", len(records))
+	return nil
+}
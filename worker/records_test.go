@@ -0,0 +1,118 @@
+package worker
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"mapreduce/keys"
+	pb "mapreduce/proto"
+)
+
+func TestRoute(t *testing.T) {
+	asc := []*pb.ReducerInfo{
+		{Address: "r0", IntervalStart: math.MinInt64, IntervalEnd: -10},
+		{Address: "r1", IntervalStart: -10, IntervalEnd: 10},
+		{Address: "r2", IntervalStart: 10, IntervalEnd: math.MaxInt64},
+	}
+	// a descending job gives the highest interval to its first reducer, the same keys reach the same addresses
+	desc := []*pb.ReducerInfo{asc[2], asc[1], asc[0]}
+	ascStrings := []*pb.ReducerInfo{
+		{Address: "r0", KeyEnd: []byte("g")},
+		{Address: "r1", KeyStart: []byte("g"), KeyEnd: []byte("p")},
+		{Address: "r2", KeyStart: []byte("p")},
+	}
+	descStrings := []*pb.ReducerInfo{ascStrings[2], ascStrings[1], ascStrings[0]}
+	tests := []struct {
+		name     string
+		reducers []*pb.ReducerInfo
+		order    keys.Order
+		records  []*pb.Record
+		want     []string
+	}{
+		{"asc", asc, keys.Order{Type: pb.KeyType_KEY_INT64},
+			[]*pb.Record{{Key: math.MinInt64}, {Key: -11}, {Key: -10}, {Key: 9}, {Key: 10}, {Key: math.MaxInt64}},
+			[]string{"r0", "r0", "r1", "r1", "r2", "r2"}},
+		{"desc", desc, keys.Order{Type: pb.KeyType_KEY_INT64, Descending: true},
+			[]*pb.Record{{Key: math.MinInt64}, {Key: -11}, {Key: -10}, {Key: 9}, {Key: 10}, {Key: math.MaxInt64}},
+			[]string{"r0", "r0", "r1", "r1", "r2", "r2"}},
+		{"asc strings", ascStrings, keys.Order{Type: pb.KeyType_KEY_STRING},
+			[]*pb.Record{{KeyBytes: nil}, {KeyBytes: []byte("f")}, {KeyBytes: []byte("g")}, {KeyBytes: []byte("p")}, {KeyBytes: []byte("zz")}},
+			[]string{"r0", "r0", "r1", "r2", "r2"}},
+		{"desc strings", descStrings, keys.Order{Type: pb.KeyType_KEY_STRING, Descending: true},
+			[]*pb.Record{{KeyBytes: nil}, {KeyBytes: []byte("f")}, {KeyBytes: []byte("g")}, {KeyBytes: []byte("p")}, {KeyBytes: []byte("zz")}},
+			[]string{"r0", "r0", "r1", "r2", "r2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := &WorkerServer{reducers: tt.reducers, order: tt.order}
+			for i, r := range tt.records {
+				if got := ws.route(r); got != tt.want[i] {
+					t.Errorf("route(%v) = %q, want %q", r, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMergeRuns(t *testing.T) {
+	// equal keys are spread over the runs, and the earliest record of each key is not in the first run
+	records := func(keyType pb.KeyType) [][]*pb.Record {
+		rec := func(key int64, seq int64) *pb.Record {
+			if keyType == pb.KeyType_KEY_STRING {
+				return &pb.Record{KeyBytes: []byte{'a' + byte(key)}, Seq: seq}
+			}
+			return &pb.Record{Key: key, Seq: seq}
+		}
+		return [][]*pb.Record{
+			{rec(1, 5), rec(2, 1), rec(2, 6), rec(3, 9)},
+			{rec(1, 2), rec(2, 7), rec(4, 4)},
+			{},
+			{rec(1, 8), rec(3, 3), rec(3, 10)},
+		}
+	}
+	for _, tt := range []struct {
+		name  string
+		order keys.Order
+	}{
+		{"asc", keys.Order{Type: pb.KeyType_KEY_INT64}},
+		{"desc", keys.Order{Type: pb.KeyType_KEY_INT64, Descending: true}},
+		{"asc strings", keys.Order{Type: pb.KeyType_KEY_STRING}},
+		{"desc strings", keys.Order{Type: pb.KeyType_KEY_STRING, Descending: true}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			compare := tt.order.Compare()
+			runs := records(tt.order.Type)
+			var all []*pb.Record
+			for _, run := range runs {
+				slices.SortFunc(run, compare)
+				all = append(all, run...)
+			}
+			want := slices.Clone(all)
+			slices.SortFunc(want, compare)
+			if got := mergeRuns(runs, len(all), compare, nil); !slices.Equal(got, want) {
+				t.Errorf("mergeRuns = %v, want %v", got, want)
+			}
+
+			runs = records(tt.order.Type)
+			for _, run := range runs {
+				slices.SortFunc(run, compare)
+			}
+			got := mergeRuns(runs, len(all), compare, tt.order.Equal())
+			// the first record of each key by seq: 1 at 2, 2 at 1, 3 at 3, 4 at 4
+			seqs := []int64{2, 1, 3, 4}
+			if tt.order.Descending {
+				slices.Reverse(seqs)
+			}
+			if len(got) != len(seqs) {
+				t.Fatalf("unique mergeRuns = %v, want %d records", got, len(seqs))
+			}
+			for i, r := range got {
+				if r.Seq != seqs[i] {
+					t.Errorf("unique mergeRuns = %v, want seqs %v", got, seqs)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	pb "mapreduce/proto"
+)
+
+// progress of the current job, read by GetStatus without taking the reducer lock
+type progress struct {
+	valuesReceived atomic.Int64
+	valuesSent     atomic.Int64
+	valuesWritten  atomic.Int64
+	duplicates     atomic.Int64
+	bytesWritten   atomic.Int64
+	mappersPending atomic.Int32
+	done           atomic.Bool
+	part           atomic.Pointer[pb.PartInfo]
+	failure        atomic.Pointer[string] // why the job failed, reported to the master
+
+	// shuffle volumes of a mapper, by reducer address
+	shuffleMu       sync.Mutex
+	valuesByReducer map[string]int64
+	bytesByReducer  map[string]int64
+}
+
+func (p *progress) reset(mappersPending int32) {
+	p.valuesReceived.Store(0)
+	p.valuesSent.Store(0)
+	p.valuesWritten.Store(0)
+	p.duplicates.Store(0)
+	p.bytesWritten.Store(0)
+	p.mappersPending.Store(mappersPending)
+	p.done.Store(false)
+	p.part.Store(nil)
+	p.failure.Store(nil)
+	p.shuffleMu.Lock()
+	p.valuesByReducer = map[string]int64{}
+	p.bytesByReducer = map[string]int64{}
+	p.shuffleMu.Unlock()
+}
+
+// fail records the first error that makes the job fail on this worker
+func (p *progress) fail(err error) {
+	msg := err.Error()
+	p.failure.CompareAndSwap(nil, &msg)
+}
+
+func (p *progress) recordShuffle(reducer string, values, bytes int64) {
+	p.valuesSent.Add(values)
+	p.shuffleMu.Lock()
+	p.valuesByReducer[reducer] += values
+	p.bytesByReducer[reducer] += bytes
+	p.shuffleMu.Unlock()
+}
+
+func (p *progress) shuffleVolumes() (values, bytes map[string]int64) {
+	p.shuffleMu.Lock()
+	defer p.shuffleMu.Unlock()
+	values = make(map[string]int64, len(p.valuesByReducer))
+	bytes = make(map[string]int64, len(p.bytesByReducer))
+	for k, v := range p.valuesByReducer {
+		values[k] = v
+	}
+	for k, v := range p.bytesByReducer {
+		bytes[k] = v
+	}
+	return values, bytes
+}
+
+func (ws *WorkerServer) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.GetStatusResponse, error) {
+	values, bytes := ws.progress.shuffleVolumes()
+	var failure string
+	if f := ws.progress.failure.Load(); f != nil {
+		failure = *f
+	}
+	return &pb.GetStatusResponse{
+		Role:           ws.role,
+		JobId:          ws.jobID,
+		ValuesReceived: ws.progress.valuesReceived.Load(),
+		MappersPending: ws.progress.mappersPending.Load(),
+		BytesWritten:   ws.progress.bytesWritten.Load(),
+		ValuesSent:     ws.progress.valuesSent.Load(),
+		ValuesWritten:  ws.progress.valuesWritten.Load(),
+		Duplicates:     ws.progress.duplicates.Load(),
+		Done:           ws.progress.done.Load(),
+
+		ValuesSentByReducer: values,
+		BytesSentByReducer:  bytes,
+		Part:                ws.progress.part.Load(),
+		Error:               failure,
+	}, nil
+}
+
+// countingWriter adds the bytes written through it to n
+type countingWriter struct {
+	w io.Writer
+	n *atomic.Int64
+}
+
+func (c countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n.Add(int64(n))
+	return n, err
+}
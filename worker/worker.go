@@ -3,48 +3,129 @@ package worker
 import (
 	"context"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
-	"sort"
+	"slices"
 	"sync"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"mapreduce/auth"
+	"mapreduce/ioformat"
+	"mapreduce/keys"
+	"mapreduce/logging"
+	"mapreduce/metrics"
 	pb "mapreduce/proto"
+	"mapreduce/psort"
+	"mapreduce/tracing"
 )
 
+// MethodRoles lists which token roles may call each worker RPC when authentication is enabled.
+var MethodRoles = map[string][]auth.Role{
+	pb.WorkerService_AssignRole_FullMethodName:       {auth.RoleMaster},
+	pb.WorkerService_SendChunk_FullMethodName:        {auth.RoleMaster},
+	pb.WorkerService_SendMappedData_FullMethodName:   {auth.RoleMapper},
+	pb.WorkerService_NotifyMapperDone_FullMethodName: {auth.RoleMapper},
+	pb.WorkerService_GetStatus_FullMethodName:        {auth.RoleMaster},
+	pb.WorkerService_FetchOutput_FullMethodName:      {auth.RoleMaster},
+	pb.WorkerService_Negotiate_FullMethodName:        {auth.RoleMapper},
+	pb.WorkerService_AssignSplit_FullMethodName:      {auth.RoleMaster},
+	pb.WorkerService_QueryRanks_FullMethodName:       {auth.RoleMaster},
+}
+
 type WorkerServer struct {
 	pb.UnimplementedWorkerServiceServer
 
 	isMapper      bool
+	role          string
+	jobID         string
 	reducers      []*pb.ReducerInfo
 	totalMappers  int32
 	intervalStart int64
 	intervalEnd   int64
+	order         keys.Order
+	unique        bool // drop values or records whose key was already seen
+	job           pb.JobKind
 
 	// Mapper state
 	mapperOnce sync.Once
+	token      string           // issued by the master, attached to calls to reducers
+	compress   bool             // gzip the values sent to reducers
+	encoding   pb.BatchEncoding // preferred encoding of the values sent to reducers
 
 	// Reducer state
-	mu            sync.Mutex
-	receivedData  []int64
-	mappersToWait int32  // how many mappers need to finish
-	BindAddress   string // to name output file
+	mu             sync.Mutex
+	receivedData   []int64
+	mappersToWait  int32 // how many mappers need to finish
+	part           int32 // rank of the interval, numbers the output file
+	outputDir      string
+	outputMode     pb.OutputMode
+	outputFormat   ioformat.Format
+	compressOutput bool
+	retained       []int64        // sorted output kept for FetchOutput in merged mode, or for QueryRanks
+	records        bool           // the job sorts records instead of values
+	runs           [][]*pb.Record // sorted batches of records received from mappers
+	pendingRecords int            // records held in runs
+	retainedOut    []*pb.Record
+	histograms     []histogram // value counts received from mappers in histogram jobs
+	retainedCounts []int64     // counts of the retained values of a histogram
+	BindAddress    string
+	SortThreads    int // goroutines used to sort, 0 means GOMAXPROCS
+
+	progress progress
 }
 
 func (ws *WorkerServer) AssignRole(ctx context.Context, req *pb.AssignRoleRequest) (*pb.AssignRoleResponse, error) {
+	if ws.jobID != "" && ws.jobID != req.JobId {
+		// spans of the previous job that ended after its trace was flushed, such as status polls
+		if err := tracing.Flush(ws.jobID); err != nil {
+			ws.log().Warn("Failed to write trace", "error", err)
+		}
+	}
 	ws.isMapper = req.IsMapper
+	ws.jobID = req.JobId
 	ws.totalMappers = req.TotalMappers
 	ws.intervalStart = req.IntervalStart
 	ws.intervalEnd = req.IntervalEnd
+	ws.order = keys.Order{Type: req.KeyType, Comparator: req.Comparator, Descending: req.Descending}
+	ws.unique = req.Unique
+	ws.job = req.Job
+	if err := ws.order.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
 
 	if ws.isMapper {
 		ws.reducers = req.Reducers
+		ws.token = req.MapperToken
+		ws.compress = req.CompressShuffle
+		ws.encoding = req.BatchEncoding
 	}
+	var pending int32
 	if !ws.isMapper {
+		ws.part = req.Partition
+		ws.outputDir = req.OutputDir
+		ws.outputMode = req.OutputMode
+		ws.outputFormat = ioformat.Format(req.OutputFormat)
+		ws.compressOutput = req.CompressOutput
+		ws.records = req.Records
+		ws.receivedData = nil
+		ws.runs = nil
+		ws.pendingRecords = 0
+		ws.retainedOut = nil
+		ws.retained = nil
+		ws.histograms = nil
+		ws.retainedCounts = nil
 		ws.mappersToWait = ws.totalMappers
+		mappersPending.Set(float64(ws.mappersToWait))
+		pending = ws.mappersToWait
 	}
+	ws.progress.reset(pending)
 
 	role := "UNASSIGNED"
 	if ws.isMapper {
@@ -52,101 +133,278 @@ func (ws *WorkerServer) AssignRole(ctx context.Context, req *pb.AssignRoleReques
 	} else if !ws.isMapper {
 		role = "REDUCER"
 	}
-	fmt.Printf("%s Assigned role: %s\n", time.Now().Format("2006/01/02 15:04:05"), role)
+	ws.role = role
+	ws.log().Info("Assigned role", "phase", "assign")
 	return &pb.AssignRoleResponse{Message: "Role: " + role}, nil
 }
 
+// JobID returns the ID of the job the worker was last assigned
+func (ws *WorkerServer) JobID() string {
+	return ws.jobID
+}
+
+// log returns a logger tagged with the worker address, its role and the current job
+func (ws *WorkerServer) log() *slog.Logger {
+	role := ws.role
+	if role == "" {
+		role = "UNASSIGNED"
+	}
+	return slog.With("worker", ws.BindAddress, "role", role, "job", ws.jobID)
+}
+
 func (ws *WorkerServer) SendChunk(ctx context.Context, req *pb.SendChunkRequest) (*pb.SendChunkResponse, error) {
 	if !ws.isMapper {
 		return &pb.SendChunkResponse{Message: "Not a mapper"}, nil
 	}
+	// the chunk is mapped and shuffled before returning, which ends the mapper's part of the trace
+	tracing.FlushOnEnd(ctx)
+
+	if len(req.Records) > 0 {
+		chunkValuesReceived.Add(float64(len(req.Records)))
+		ws.progress.valuesReceived.Add(int64(len(req.Records)))
+		ws.log().Info("Received chunk", "phase", "map", "records", len(req.Records))
+		ws.mapRecords(ctx, req.Records)
+		return &pb.SendChunkResponse{Message: "Mapper finished sending data."}, nil
+	}
 
 	// Mapper: we got a chunk of data
 	values := req.Values
+	chunkValuesReceived.Add(float64(len(values)))
+	ws.progress.valuesReceived.Add(int64(len(values)))
+	ws.log().Info("Received chunk", "phase", "map", "values", len(values))
 
-	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
-	// Distribute values to reducers based on intervals
-	var subChunk []int64
-	var prevTarget string
-	for i, v := range values {
-		target := ws.findReducer(v)
-		if target == "" {
-			log.Printf("Mapper: no reducer found for value %d, skipping", v)
-			continue
-		}
+	ws.mapValues(ctx, values)
+	return &pb.SendChunkResponse{Message: "Mapper finished sending data."}, nil
+}
 
-		if prevTarget == "" {
-			prevTarget = target
+// AssignSplit reads splits of the input from the shared path, then maps them together like a chunk.
+func (ws *WorkerServer) AssignSplit(ctx context.Context, req *pb.AssignSplitRequest) (*pb.AssignSplitResponse, error) {
+	if !ws.isMapper {
+		return &pb.AssignSplitResponse{Message: "Not a mapper"}, nil
+	}
+	tracing.FlushOnEnd(ctx)
+	if req.KeyField > 0 || keys.Bytes(req.KeyType) {
+		return ws.assignRecordSplits(ctx, req)
+	}
+
+	var values []int64
+	for _, split := range req.Splits {
+		_, span := tracing.Start(ctx, "read split", "path", split.Path, "offset", split.Offset, "length", split.Length)
+		read, err := readSplit(split, keys.Numeric(req.KeyType), values)
+		span.SetAttrs("values", len(read)-len(values))
+		span.End()
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "read split %s@%d+%d: %v", split.Path, split.Offset, split.Length, err)
 		}
+		ws.log().Info("Read split", "phase", "map", "path", split.Path, "offset", split.Offset,
+			"length", split.Length, "values", len(read)-len(values))
+		chunkValuesReceived.Add(float64(len(read) - len(values)))
+		ws.progress.valuesReceived.Add(int64(len(read) - len(values)))
+		values = read
+	}
+
+	ws.mapValues(ctx, values)
+	return &pb.AssignSplitResponse{Message: "Mapper finished sending data.", Values: int64(len(values))}, nil
+}
 
-		if prevTarget == target {
-			subChunk = append(subChunk, v)
+// readSplit appends the keys of the values of type t of split to values
+func readSplit(split *pb.InputSplit, t ioformat.Type, values []int64) ([]int64, error) {
+	f, err := os.Open(split.Path)
+	if err != nil {
+		return values, err
+	}
+	defer f.Close()
+	r, err := ioformat.NewReader(io.NewSectionReader(f, split.Offset, split.Length), ioformat.Format(split.Format), t)
+	if err != nil {
+		return values, err
+	}
+	for {
+		v, err := r.Read()
+		if err == io.EOF {
+			return values, nil
 		}
-		if prevTarget != target || i == len(values)-1 {
-			err := ws.sendToReducer(prevTarget, subChunk)
-			if err != nil {
-				log.Printf("Failed to send values from %d to %d to reducer %s: %v", subChunk[0], subChunk[len(subChunk)-1], prevTarget, err)
-			} else {
-				fmt.Printf("%s Sent %d values from %d to %d  to reducer %s\n", time.Now().Format("2006/01/02 15:04:05"), len(subChunk), subChunk[0], subChunk[len(subChunk)-1], prevTarget)
-			}
-			subChunk = subChunk[:0]
-			subChunk = append(subChunk, v)
+		if err != nil {
+			return values, err
+		}
+		values = append(values, v)
+	}
+}
+
+// mapValues sorts the values of a chunk or split, sends each reducer its range, then notifies every reducer
+func (ws *WorkerServer) mapValues(ctx context.Context, values []int64) {
+	_, span := tracing.Start(ctx, "sort", "values", len(values))
+	sortStart := time.Now()
+	psort.Int64s(values, ws.SortThreads)
+	sortDuration.With("mapper").Observe(time.Since(sortStart).Seconds())
+	span.End()
+	if ws.unique {
+		// fewer values to shuffle, reducers drop the duplicates found by other mappers
+		n := len(values)
+		values = slices.Compact(values)
+		ws.progress.duplicates.Add(int64(n - len(values)))
+	}
+	var counts []int64
+	if ws.job == pb.JobKind_JOB_HISTOGRAM {
+		// reducers only receive each value once, with its count
+		values, counts = countValues(values)
+	}
+
+	// Distribute values to reducers based on intervals
+	_, span = tracing.Start(ctx, "partition")
+	batches := ws.partition(len(values),
+		func(i int) string { return ws.findReducer(values[i]) },
+		func(i int) any { return values[i] })
+	for i := range batches {
+		batches[i].values = values[batches[i].start:batches[i].end]
+		if counts != nil {
+			batches[i].counts = counts[batches[i].start:batches[i].end]
 		}
-		prevTarget = target
+	}
+	span.SetAttrs("batches", len(batches))
+	span.End()
 
+	ws.shuffle(ctx, batches)
+}
+
+// shuffle sends each batch to its reducer, then notifies every reducer that this mapper is done
+func (ws *WorkerServer) shuffle(ctx context.Context, batches []batch) {
+	logger := ws.log()
+	conns := peers{}
+	defer conns.close(ws)
+	for _, b := range batches {
+		batchCtx, span := tracing.Start(ctx, "shuffle batch", "reducer", b.reducer, "values", b.end-b.start)
+		p, err := conns.get(batchCtx, ws, b.reducer)
+		if err == nil {
+			span.SetAttrs("encoding", p.encoding.String())
+			err = ws.sendToReducer(batchCtx, p, b)
+		}
+		if err != nil {
+			span.SetAttrs("error", err.Error())
+			logger.Error("Failed to send values to reducer", "phase", "shuffle", "reducer", b.reducer,
+				"first", b.first, "last", b.last, "error", err)
+			ws.progress.fail(fmt.Errorf("send values to %s: %w", b.reducer, err))
+		} else {
+			logger.Info("Sent values to reducer", "phase", "shuffle", "reducer", b.reducer,
+				"values", b.end-b.start, "first", b.first, "last", b.last)
+		}
+		span.End()
 	}
 
 	// After finished sending, notify reducers we are done
 	for _, r := range ws.reducers {
-		err := ws.notifyMapperDone(r.Address)
+		p, err := conns.get(ctx, ws, r.Address)
+		if err == nil {
+			err = ws.notifyMapperDone(ctx, p)
+		}
 		if err != nil {
-			log.Printf("Failed to notify done to %s: %v", r.Address, err)
+			logger.Error("Failed to notify done", "phase", "shuffle", "reducer", r.Address, "error", err)
+			ws.progress.fail(fmt.Errorf("notify %s: %w", r.Address, err))
 		}
 	}
+	ws.progress.done.Store(true)
+}
 
-	return &pb.SendChunkResponse{Message: "Mapper finished sending data."}, nil
+// batch is a run [start, end) of sorted values or records that all belong to the same reducer
+type batch struct {
+	reducer     string
+	start, end  int
+	first, last any // keys at both ends, for logs
+	values      []int64
+	counts      []int64 // occurrences of the values in histogram jobs
+	records     []*pb.Record
 }
 
+// partition splits n sorted keys into contiguous runs, one per target reducer as given by route.
+// Keys outside every reducer interval are dropped, key describes them in logs.
+func (ws *WorkerServer) partition(n int, route func(i int) string, key func(i int) any) []batch {
+	var batches []batch
+	start := 0
+	prevTarget := ""
+	flush := func(end int) {
+		if prevTarget != "" && start < end {
+			batches = append(batches, batch{reducer: prevTarget, start: start, end: end, first: key(start), last: key(end - 1)})
+		}
+	}
+	for i := 0; i < n; i++ {
+		target := route(i)
+		if target != prevTarget {
+			flush(i)
+			start = i
+			prevTarget = target
+		}
+		if target == "" {
+			ws.log().Warn("No reducer found for value, skipping", "phase", "shuffle", "value", key(i))
+			start = i + 1
+		}
+	}
+	flush(n)
+	return batches
+}
+
+// findReducer returns the address of the reducer whose interval holds val.
+// The highest interval has no upper bound, so that it holds math.MaxInt64.
 func (ws *WorkerServer) findReducer(val int64) string {
-	for _, r := range ws.reducers {
-		if val >= r.IntervalStart && val < r.IntervalEnd {
+	for i, r := range ws.reducers {
+		_, highest := ws.order.Bounds(i, len(ws.reducers))
+		if val >= r.IntervalStart && (highest || val < r.IntervalEnd) {
 			return r.Address
 		}
 	}
 	return ""
 }
 
-func (ws *WorkerServer) sendToReducer(addr string, values []int64) error {
-	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		return err
+func (ws *WorkerServer) dial(addr string) (*grpc.ClientConn, error) {
+	return grpc.Dial(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(
+			metrics.UnaryClientInterceptor(),
+			tracing.UnaryClientInterceptor(),
+			auth.UnaryClientInterceptor(func() string { return ws.token }),
+		),
+	)
+}
+
+func (ws *WorkerServer) sendToReducer(ctx context.Context, p *peer, b batch) error {
+	addr := p.addr
+	req := &pb.SendMappedDataRequest{
+		ReducerAddress: addr,
 	}
-	defer func() {
-		if err := conn.Close(); err != nil {
-			log.Printf("Failed to close connection: %v", err)
+	values := b.values
+	req.Counts = b.counts
+	n := int64(b.end - b.start)
+	if b.counts != nil {
+		n = countSum(b.counts)
+	}
+	switch {
+	case b.records != nil:
+		req.Records = b.records
+	case p.encoding == pb.BatchEncoding_BATCH_RAW:
+		req.Values = values
+	default:
+		encoded, err := encodeBatch(values, p.encoding)
+		if err != nil {
+			return err
 		}
-	}()
-	client := pb.NewWorkerServiceClient(conn)
-	_, err = client.SendMappedData(context.Background(), &pb.SendMappedDataRequest{
-		Values:         values,
-		ReducerAddress: addr,
-	})
+		req.Encoded = encoded
+	}
+	var opts []grpc.CallOption
+	if ws.compress {
+		opts = append(opts, grpc.UseCompressor(gzip.Name))
+	}
+	_, err := p.client.SendMappedData(ctx, req, opts...)
+	if err == nil {
+		size := proto.Size(req)
+		spills.With(addr).Inc()
+		shuffleValues.With(addr).Add(float64(n))
+		shuffleBytes.With(addr).Add(float64(size))
+		ws.progress.recordShuffle(addr, n, int64(size))
+	}
 	return err
 }
 
-func (ws *WorkerServer) notifyMapperDone(addr string) error {
-	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err := conn.Close(); err != nil {
-			log.Printf("Failed to close connection: %v", err)
-		}
-	}()
-	client := pb.NewWorkerServiceClient(conn)
+func (ws *WorkerServer) notifyMapperDone(ctx context.Context, p *peer) error {
 	host, _ := os.Hostname()
-	_, err = client.NotifyMapperDone(context.Background(), &pb.NotifyMapperDoneRequest{
+	_, err := p.client.NotifyMapperDone(ctx, &pb.NotifyMapperDoneRequest{
 		MapperAddress: host,
 	})
 	return err
@@ -158,9 +416,37 @@ func (ws *WorkerServer) SendMappedData(ctx context.Context, req *pb.SendMappedDa
 		return &pb.Empty{}, nil
 	}
 
+	values := req.Values
+	if req.Encoded != nil {
+		var err error
+		values, err = decodeBatch(req.Encoded)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "decode batch: %v", err)
+		}
+	}
+
+	n := int64(len(values) + len(req.Records))
+	if len(req.Counts) > 0 {
+		if len(req.Counts) != len(values) {
+			return nil, status.Errorf(codes.InvalidArgument, "%d counts for %d values", len(req.Counts), len(values))
+		}
+		n = countSum(req.Counts)
+	}
+
 	ws.mu.Lock()
-	ws.receivedData = append(ws.receivedData, req.Values...)
+	switch {
+	case len(req.Counts) > 0:
+		ws.histograms = append(ws.histograms, histogram{values: values, counts: req.Counts})
+	case len(req.Records) > 0:
+		ws.runs = append(ws.runs, req.Records)
+		ws.pendingRecords += len(req.Records)
+	default:
+		ws.receivedData = append(ws.receivedData, values...)
+	}
+	reducerQueueSize.Set(float64(len(ws.receivedData) + ws.pendingRecords))
 	ws.mu.Unlock()
+	mappedValuesReceived.Add(float64(n))
+	ws.progress.valuesReceived.Add(n)
 	return &pb.Empty{}, nil
 }
 
@@ -172,54 +458,82 @@ func (ws *WorkerServer) NotifyMapperDone(ctx context.Context, req *pb.NotifyMapp
 	ws.mappersToWait--
 	waiting := ws.mappersToWait
 	ws.mu.Unlock()
+	mappersPending.Set(float64(waiting))
+	ws.progress.mappersPending.Store(waiting)
 
 	if waiting == 0 {
-		// All mappers finished, finalize reduce
-		ws.finalizeReduce()
+		// All mappers finished, finalize reduce in the background: the last mapper notifies the other
+		// reducers after this one, and its deadline is the master's for the chunk, not for the reduce.
+		// The master learns the outcome from GetStatus.
+		ctx := context.WithoutCancel(ctx)
+		jobID := ws.jobID
+		go func() {
+			ws.finalizeReduce(ctx)
+			if err := tracing.Flush(jobID); err != nil {
+				ws.log().Warn("Failed to write trace", "error", err)
+			}
+		}()
 	}
 
 	return &pb.Empty{}, nil
 }
 
-func (ws *WorkerServer) finalizeReduce() {
+func (ws *WorkerServer) finalizeReduce(ctx context.Context) {
 	ws.mu.Lock()
 	defer ws.mu.Unlock()
-	fmt.Printf("%s Received data: %v\n", time.Now().Format("2006/01/02 15:04:05"), ws.receivedData)
-	sort.Slice(ws.receivedData, func(i, j int) bool {
-		return ws.receivedData[i] < ws.receivedData[j]
-	})
-	fmt.Printf("%s Sorted data: %v\n", time.Now().Format("2006/01/02 15:04:05"), ws.receivedData)
+	if ws.records {
+		ws.finalizeRecords(ctx)
+		return
+	}
+	if ws.job == pb.JobKind_JOB_HISTOGRAM {
+		ws.finalizeHistogram(ctx)
+		return
+	}
+	logger := ws.log()
+	logger.Info("All mappers done, reducing", "phase", "reduce", "values", len(ws.receivedData))
+	logger.Debug("Received data", "phase", "reduce", "values", logging.Preview(ws.receivedData))
+	_, span := tracing.Start(ctx, "sort", "values", len(ws.receivedData))
+	sortStart := time.Now()
+	psort.Int64s(ws.receivedData, ws.SortThreads)
+	if ws.order.Descending {
+		slices.Reverse(ws.receivedData)
+	}
+	if ws.unique {
+		n := len(ws.receivedData)
+		ws.receivedData = slices.Compact(ws.receivedData)
+		ws.progress.duplicates.Add(int64(n - len(ws.receivedData)))
+	}
+	sortDuration.With("reducer").Observe(time.Since(sortStart).Seconds())
+	span.End()
+	logger.Debug("Sorted data", "phase", "reduce", "values", logging.Preview(ws.receivedData))
+	if ws.outputMode == pb.OutputMode_OUTPUT_MERGED || ws.job == pb.JobKind_JOB_QUANTILES {
+		// the master fetches the sorted data into the merged file, or queries ranks in it
+		ws.retained = ws.receivedData
+		ws.receivedData = []int64{}
+		reducerQueueSize.Set(0)
+		ws.progress.part.Store(ws.partInfo(len(ws.retained), func(i int) int64 { return ws.retained[i] }))
+		ws.progress.done.Store(true)
+		logger.Info("Output ready to be fetched", "phase", "write", "records", len(ws.retained))
+		return
+	}
+
 	// Write to file
-	outputFile := fmt.Sprintf("reducer_%s_output.txt", makeSafeFileName(ws.BindAddress))
-	f, err := os.Create(outputFile)
+	_, span = tracing.Start(ctx, "write file")
+	part, err := ws.writePart(ws.receivedData, nil, nil)
+	span.End()
 	if err != nil {
-		log.Printf("Reducer failed to create output file: %v", err)
+		logger.Error("Failed to write output part", "phase", "write", "error", err)
+		ws.progress.fail(fmt.Errorf("write output part: %w", err))
+		ws.receivedData = nil
+		reducerQueueSize.Set(0)
 		return
 	}
-	defer f.Close()
-	for _, v := range ws.receivedData {
-		fmt.Fprintln(f, v)
-	}
+	ws.progress.part.Store(part)
+	ws.progress.done.Store(true)
 
 	// Empty the receivedData slice
 	ws.receivedData = []int64{}
+	reducerQueueSize.Set(0)
 
-	fmt.Printf("%s Wrote output to %s\n", time.Now().Format("2006/01/02 15:04:05"), outputFile)
-}
-
-func makeSafeFileName(addr string) string {
-	// Replace ':' with '_'
-	return stringReplaceAll(addr, ":", "_")
-}
-
-func stringReplaceAll(s, old, new string) string {
-	returnValue := ""
-	for _, c := range s {
-		if string(c) == old {
-			returnValue += new
-		} else {
-			returnValue += string(c)
-		}
-	}
-	return returnValue
+	logger.Info("Wrote output", "phase", "write", "path", part.Path, "records", part.Records)
 }